@@ -0,0 +1,43 @@
+package djoemo
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// UnprocessedKeysError is returned by a batch get if dynamodb still left keys unprocessed after maxUnprocessedRetries attempts
+type UnprocessedKeysError struct {
+	// TableName is the table of the batch get
+	TableName string
+	// Keys are the keys that were not read, including the keys of chunks that were not requested yet
+	Keys []map[string]*dynamodb.AttributeValue
+}
+
+// Error returns the number of unprocessed keys
+func (e *UnprocessedKeysError) Error() string {
+	return fmt.Sprintf("%s: %d keys of table %s", ErrUnprocessedBatchRequest.Error(), len(e.Keys), e.TableName)
+}
+
+// Is makes the error match ErrUnprocessedBatchRequest
+func (e *UnprocessedKeysError) Is(target error) bool {
+	return target == ErrUnprocessedBatchRequest
+}
+
+// UnprocessedItemsError is returned by a batch write if dynamodb still left requests unprocessed after maxUnprocessedRetries attempts
+type UnprocessedItemsError struct {
+	// TableName is the table of the batch write
+	TableName string
+	// Requests are the write requests that were not applied, including the requests of chunks that were not sent yet
+	Requests []*dynamodb.WriteRequest
+}
+
+// Error returns the number of unprocessed requests
+func (e *UnprocessedItemsError) Error() string {
+	return fmt.Sprintf("%s: %d write requests of table %s", ErrUnprocessedBatchRequest.Error(), len(e.Requests), e.TableName)
+}
+
+// Is makes the error match ErrUnprocessedBatchRequest
+func (e *UnprocessedItemsError) Is(target error) bool {
+	return target == ErrUnprocessedBatchRequest
+}
@@ -0,0 +1,240 @@
+package djoemo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+const (
+	// minCapacityRateFraction is the fraction of the configured rate a limiter slows down to at most when it is throttled
+	minCapacityRateFraction = 0.05
+	// capacityRateRecoveryFraction is the fraction of the configured rate a throttled limiter speeds up by per second
+	capacityRateRecoveryFraction = 0.1
+	// maxThrottledRetries is the number of times a request that was throttled is retried by a limited operation
+	maxThrottledRetries = 10
+	// throttledPause is the time a throttled limiter pauses requests for
+	throttledPause = 100 * time.Millisecond
+)
+
+// CapacityLimiter caps the read and write capacity units consumed per second by the scans, batch operations and scan counts called
+// with a context it is set on with WithCapacityLimiter, or by the scans it is set on with WithScanCapacityLimiter; the consumed capacity
+// reported by dynamodb is taken from the budget, so a request waits until the capacity consumed before is paid off. If dynamodb
+// rejects requests because the provisioned throughput is exceeded, the limiter halves its rate and speeds up again over time.
+// A limiter is safe for concurrent use, so it can be shared by the segments of a parallel scan and by several jobs
+type CapacityLimiter struct {
+	read  *capacityBucket
+	write *capacityBucket
+}
+
+// NewCapacityLimiter factory method for a limiter of readUnitsPerSecond read and writeUnitsPerSecond write capacity units per second;
+// 0 does not limit the capacity
+func NewCapacityLimiter(readUnitsPerSecond, writeUnitsPerSecond float64) *CapacityLimiter {
+	return &CapacityLimiter{
+		read:  newCapacityBucket(readUnitsPerSecond),
+		write: newCapacityBucket(writeUnitsPerSecond),
+	}
+}
+
+// ReadUnitsPerSecond returns the current read rate, it is lower than the configured rate while the limiter slows down
+func (l *CapacityLimiter) ReadUnitsPerSecond() float64 {
+	return l.reads().currentRate()
+}
+
+// WriteUnitsPerSecond returns the current write rate, it is lower than the configured rate while the limiter slows down
+func (l *CapacityLimiter) WriteUnitsPerSecond() float64 {
+	return l.writes().currentRate()
+}
+
+// reads returns the bucket of read capacity, nil if reads are not limited
+func (l *CapacityLimiter) reads() *capacityBucket {
+	if l == nil {
+		return nil
+	}
+	return l.read
+}
+
+// writes returns the bucket of write capacity, nil if writes are not limited
+func (l *CapacityLimiter) writes() *capacityBucket {
+	if l == nil {
+		return nil
+	}
+	return l.write
+}
+
+// capacityBucket is a token bucket of capacity units that may run into debt, since the capacity of a request is known only after it;
+// all methods accept a nil bucket, which does not limit
+type capacityBucket struct {
+	mu sync.Mutex
+	// maxRate is the configured rate, rate the current one
+	maxRate float64
+	rate    float64
+	// tokens are the units that can be consumed, negative while the capacity consumed before is not paid off
+	tokens float64
+	last   time.Time
+}
+
+func newCapacityBucket(unitsPerSecond float64) *capacityBucket {
+	if unitsPerSecond <= 0 {
+		return nil
+	}
+
+	return &capacityBucket{
+		maxRate: unitsPerSecond,
+		rate:    unitsPerSecond,
+		tokens:  unitsPerSecond,
+		last:    time.Now(),
+	}
+}
+
+// wait blocks until the capacity consumed before is paid off
+// returns the error of the context if it is done before
+func (b *capacityBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	for {
+		b.mu.Lock()
+		b.refill()
+		delay := time.Duration(-b.tokens / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		if delay <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// consume takes the units consumed by a request that succeeded from the budget
+func (b *capacityBucket) consume(units float64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.tokens -= units
+}
+
+// throttled halves the rate after dynamodb rejected a request because the provisioned throughput is exceeded
+func (b *capacityBucket) throttled() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.rate = max(b.rate/2, b.maxRate*minCapacityRateFraction)
+	// the next request waits for the capacity consumed before and a pause at the slower rate
+	b.tokens = min(b.tokens, 0) - b.rate*throttledPause.Seconds()
+}
+
+func (b *capacityBucket) currentRate() float64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	return b.rate
+}
+
+// refill adds the units earned since the last refill, at most a second of budget is kept, and speeds up a throttled bucket
+// by the time passed, so the rate recovers at the same pace however many requests are sent; it must be called with the lock held
+func (b *capacityBucket) refill() {
+	now := time.Now()
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = min(b.tokens+elapsed*b.rate, b.rate)
+	b.rate = min(b.rate+elapsed*b.maxRate*capacityRateRecoveryFraction, b.maxRate)
+	b.last = now
+}
+
+// limitedRequest sends a request that consumes capacity of bucket: it waits until the budget allows the request, retries it
+// if it is throttled and takes the consumed units returned by send from the budget; with a nil bucket the request is sent once.
+// guregu/dynamo does not report the capacity of every request it sends, so limited requests are sent by the client directly
+func limitedRequest(ctx context.Context, bucket *capacityBucket, send func() (float64, error)) error {
+	for attempt := 0; ; attempt++ {
+		if err := bucket.wait(ctx); err != nil {
+			return err
+		}
+
+		units, err := send()
+		if err != nil {
+			if bucket != nil && isThrottled(err) && attempt < maxThrottledRetries {
+				bucket.throttled()
+				continue
+			}
+			return err
+		}
+
+		bucket.consume(units)
+		return nil
+	}
+}
+
+// limitedReturnConsumedCapacity returns the consumed capacity to request: the capacity of every index if it is recorded,
+// or else the total capacity if the bucket needs it
+func limitedReturnConsumedCapacity(cc *dynamo.ConsumedCapacity, bucket *capacityBucket) *string {
+	if returnCapacity := returnConsumedCapacity(cc); returnCapacity != nil || bucket == nil {
+		return returnCapacity
+	}
+
+	return aws.String(dynamodb.ReturnConsumedCapacityTotal)
+}
+
+// capacityUnits returns the capacity units of the consumed capacities of a response
+func capacityUnits(consumed ...*dynamodb.ConsumedCapacity) float64 {
+	var units float64
+	for _, capacity := range consumed {
+		if capacity != nil && capacity.CapacityUnits != nil {
+			units += *capacity.CapacityUnits
+		}
+	}
+
+	return units
+}
+
+// isThrottled returns true if dynamodb rejected a request because the provisioned throughput or a request limit is exceeded
+func isThrottled(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+
+	switch awsErr.Code() {
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded, "ThrottlingException":
+		return true
+	}
+	return false
+}
+
+type capacityLimiterContextKey int
+
+const capacityLimiterCtxKey capacityLimiterContextKey = iota
+
+// WithCapacityLimiter limits the capacity consumed by the scans, batch gets, batch saves, batch deletes and scan counts called with
+// the returned context; a scan passed WithScanCapacityLimiter is limited by that limiter instead
+func WithCapacityLimiter(ctx context.Context, limiter *CapacityLimiter) context.Context {
+	return context.WithValue(ctx, capacityLimiterCtxKey, limiter)
+}
+
+// capacityLimiterFromContext returns the limiter of the context, nil if the capacity is not limited
+func capacityLimiterFromContext(ctx context.Context) *CapacityLimiter {
+	limiter, _ := ctx.Value(capacityLimiterCtxKey).(*CapacityLimiter)
+	return limiter
+}
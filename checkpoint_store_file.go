@@ -0,0 +1,81 @@
+package djoemo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// FileCheckpointStore stores every checkpoint as JSON file in a local directory
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore factory method for a checkpoint store that writes to dir; dir is created when the first checkpoint is saved
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{dir: dir}
+}
+
+// Save writes the checkpoint to the file of name; the file is replaced atomically, so a crash never leaves a partial checkpoint
+func (s *FileCheckpointStore) Save(_ context.Context, name string, checkpoint ScanCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.path(name))
+}
+
+// Load reads the checkpoint from the file of name
+// returns false if the file does not exist, returns false and an error in case of error
+func (s *FileCheckpointStore) Load(_ context.Context, name string) (ScanCheckpoint, bool, error) {
+	data, err := os.ReadFile(s.path(name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ScanCheckpoint{}, false, nil
+		}
+		return ScanCheckpoint{}, false, err
+	}
+
+	var checkpoint ScanCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return ScanCheckpoint{}, false, err
+	}
+
+	return checkpoint, true, nil
+}
+
+// Delete removes the file of name
+func (s *FileCheckpointStore) Delete(_ context.Context, name string) error {
+	if err := os.Remove(s.path(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// path returns the file of name; the name is escaped, so it cannot point outside of the directory
+func (s *FileCheckpointStore) path(name string) string {
+	return filepath.Join(s.dir, url.PathEscape(name)+".json")
+}
@@ -0,0 +1,16 @@
+package djoemo
+
+import "context"
+
+// CheckpointStoreInterface provides an interface to store the checkpoints of resumable scans by name
+//
+//go:generate mockgen -source=checkpoint_store_interface.go -destination=./mock/checkpoint_store_interface.go -package=mock .
+type CheckpointStoreInterface interface {
+	// Save stores the checkpoint under name, replacing the checkpoint stored before
+	Save(ctx context.Context, name string, checkpoint ScanCheckpoint) error
+	// Load returns the checkpoint stored under name
+	// returns false if no checkpoint is stored, returns false and an error in case of error
+	Load(ctx context.Context, name string) (ScanCheckpoint, bool, error)
+	// Delete removes the checkpoint stored under name; it is not an error if no checkpoint is stored
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,253 @@
+package djoemo
+
+import "strings"
+
+// AttributeType is the dynamodb data type of an attribute, used with Type conditions
+type AttributeType string
+
+// Data types of dynamodb attributes.
+const (
+	TypeString    AttributeType = "S"
+	TypeNumber    AttributeType = "N"
+	TypeBinary    AttributeType = "B"
+	TypeStringSet AttributeType = "SS"
+	TypeNumberSet AttributeType = "NS"
+	TypeBinarySet AttributeType = "BS"
+	TypeBool      AttributeType = "BOOL"
+	TypeNull      AttributeType = "NULL"
+	TypeList      AttributeType = "L"
+	TypeMap       AttributeType = "M"
+)
+
+// Condition is a condition or filter expression with its args; it renders to the placeholder syntax, so it can be used
+// wherever an expression and its args are accepted, e.g.
+// repository.ConditionalUpdateWithContext(ctx, key, item, condition.Expression(), condition.Args()...)
+// Attribute names are always passed as $ placeholders, so reserved words need no escaping. A condition that cannot be
+// rendered, like In without values, is invalid: it has no expression and Err returns ErrInvalidCondition
+type Condition struct {
+	expression string
+	args       []any
+	err        error
+}
+
+// Expression returns the expression with ? placeholders for values and $ placeholders for attribute names
+func (c Condition) Expression() string {
+	return c.expression
+}
+
+// Args returns the values and attribute names of the placeholders of the expression, in order
+func (c Condition) Args() []any {
+	return c.args
+}
+
+// IsEmpty returns true if the condition has no expression and is valid
+func (c Condition) IsEmpty() bool {
+	return c.expression == "" && c.err == nil
+}
+
+// Err returns ErrInvalidCondition if the condition or one of the conditions it combines is invalid, nil otherwise
+func (c Condition) Err() error {
+	return c.err
+}
+
+// AttributePath is an attribute of an item that conditions compare; nested attributes are separated by dots
+// and list elements are addressed by index, e.g. "Address.Lines[0]"
+type AttributePath struct {
+	path string
+}
+
+// Attribute returns the attribute with the given path to build conditions on
+func Attribute(path string) AttributePath {
+	return AttributePath{path: path}
+}
+
+// Eq is true if the attribute equals value
+func (a AttributePath) Eq(value any) Condition {
+	return a.compare("=", value)
+}
+
+// Ne is true if the attribute does not equal value
+func (a AttributePath) Ne(value any) Condition {
+	return a.compare("<>", value)
+}
+
+// Lt is true if the attribute is less than value
+func (a AttributePath) Lt(value any) Condition {
+	return a.compare("<", value)
+}
+
+// Le is true if the attribute is less than or equal to value
+func (a AttributePath) Le(value any) Condition {
+	return a.compare("<=", value)
+}
+
+// Gt is true if the attribute is greater than value
+func (a AttributePath) Gt(value any) Condition {
+	return a.compare(">", value)
+}
+
+// Ge is true if the attribute is greater than or equal to value
+func (a AttributePath) Ge(value any) Condition {
+	return a.compare(">=", value)
+}
+
+// Between is true if the attribute is greater than or equal to lo and less than or equal to hi
+func (a AttributePath) Between(lo, hi any) Condition {
+	path, args := a.placeholders()
+	return Condition{expression: path + " BETWEEN ? AND ?", args: append(args, lo, hi)}
+}
+
+// In is true if the attribute equals any of values; dynamodb accepts up to 100 values. Without values the condition is invalid
+func (a AttributePath) In(values ...any) Condition {
+	if len(values) == 0 {
+		return Condition{err: ErrInvalidCondition}
+	}
+	path, args := a.placeholders()
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return Condition{expression: path + " IN (" + placeholders + ")", args: append(args, values...)}
+}
+
+// BeginsWith is true if the string attribute starts with prefix
+func (a AttributePath) BeginsWith(prefix string) Condition {
+	return a.function("begins_with", prefix)
+}
+
+// Contains is true if the string attribute contains value as substring or the set or list attribute contains value as element
+func (a AttributePath) Contains(value any) Condition {
+	return a.function("contains", value)
+}
+
+// Exists is true if the item has the attribute
+func (a AttributePath) Exists() Condition {
+	path, args := a.placeholders()
+	return Condition{expression: "attribute_exists(" + path + ")", args: args}
+}
+
+// NotExists is true if the item does not have the attribute
+func (a AttributePath) NotExists() Condition {
+	path, args := a.placeholders()
+	return Condition{expression: "attribute_not_exists(" + path + ")", args: args}
+}
+
+// Type is true if the attribute is of the given data type
+func (a AttributePath) Type(attributeType AttributeType) Condition {
+	return a.function("attribute_type", string(attributeType))
+}
+
+// Size returns the size of the attribute to compare: the length of a string or binary or the number of elements of a set, list or map
+func (a AttributePath) Size() SizeOperand {
+	return SizeOperand{attribute: a}
+}
+
+func (a AttributePath) compare(operator string, value any) Condition {
+	path, args := a.placeholders()
+	return Condition{expression: path + " " + operator + " ?", args: append(args, value)}
+}
+
+func (a AttributePath) function(name string, value any) Condition {
+	path, args := a.placeholders()
+	return Condition{expression: name + "(" + path + ", ?)", args: append(args, value)}
+}
+
+// placeholders returns the path with a $ placeholder for every attribute name, and the names as args
+func (a AttributePath) placeholders() (string, []any) {
+	elements := strings.Split(a.path, ".")
+	args := make([]any, 0, len(elements))
+	for i, element := range elements {
+		name, index, hasIndex := strings.Cut(element, "[")
+		elements[i] = "$"
+		if hasIndex {
+			elements[i] += "[" + index
+		}
+		args = append(args, name)
+	}
+
+	return strings.Join(elements, "."), args
+}
+
+// SizeOperand is the size of an attribute that conditions compare
+type SizeOperand struct {
+	attribute AttributePath
+}
+
+// Eq is true if the size equals size
+func (s SizeOperand) Eq(size int) Condition {
+	return s.compare("=", size)
+}
+
+// Ne is true if the size does not equal size
+func (s SizeOperand) Ne(size int) Condition {
+	return s.compare("<>", size)
+}
+
+// Lt is true if the size is less than size
+func (s SizeOperand) Lt(size int) Condition {
+	return s.compare("<", size)
+}
+
+// Le is true if the size is less than or equal to size
+func (s SizeOperand) Le(size int) Condition {
+	return s.compare("<=", size)
+}
+
+// Gt is true if the size is greater than size
+func (s SizeOperand) Gt(size int) Condition {
+	return s.compare(">", size)
+}
+
+// Ge is true if the size is greater than or equal to size
+func (s SizeOperand) Ge(size int) Condition {
+	return s.compare(">=", size)
+}
+
+// Between is true if the size is greater than or equal to lo and less than or equal to hi
+func (s SizeOperand) Between(lo, hi int) Condition {
+	path, args := s.attribute.placeholders()
+	return Condition{expression: "size(" + path + ") BETWEEN ? AND ?", args: append(args, lo, hi)}
+}
+
+func (s SizeOperand) compare(operator string, size int) Condition {
+	path, args := s.attribute.placeholders()
+	return Condition{expression: "size(" + path + ") " + operator + " ?", args: append(args, size)}
+}
+
+// And is true if all conditions are true; empty conditions are skipped, the result is invalid if any condition is invalid
+func And(conditions ...Condition) Condition {
+	return join(" AND ", conditions)
+}
+
+// Or is true if any of the conditions is true; empty conditions are skipped, the result is invalid if any condition is invalid
+func Or(conditions ...Condition) Condition {
+	return join(" OR ", conditions)
+}
+
+// Not is true if the condition is false; the negation of an empty or invalid condition is the condition itself
+func Not(condition Condition) Condition {
+	if condition.expression == "" {
+		return condition
+	}
+	return Condition{expression: "NOT (" + condition.expression + ")", args: condition.args}
+}
+
+// join combines the conditions with operator; every condition is wrapped in parentheses, so the precedence
+// of nested conditions is kept
+func join(operator string, conditions []Condition) Condition {
+	var expressions []string
+	var args []any
+	for _, condition := range conditions {
+		if condition.err != nil {
+			return Condition{err: condition.err}
+		}
+		if condition.IsEmpty() {
+			continue
+		}
+		expressions = append(expressions, condition.expression)
+		args = append(args, condition.args...)
+	}
+
+	if len(expressions) <= 1 {
+		return Condition{expression: strings.Join(expressions, ""), args: args}
+	}
+
+	return Condition{expression: "(" + strings.Join(expressions, ")"+operator+"(") + ")", args: args}
+}
@@ -0,0 +1,140 @@
+package djoemo_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Condition", func() {
+	DescribeTable("should render to the placeholder syntax",
+		func(condition djoemo.Condition, expression string, args []any) {
+			Expect(condition.Expression()).To(Equal(expression))
+			Expect(condition.Args()).To(Equal(args))
+		},
+		Entry("Eq", djoemo.Attribute("Status").Eq("active"), "$ = ?", []any{"Status", "active"}),
+		Entry("Ne", djoemo.Attribute("Status").Ne("active"), "$ <> ?", []any{"Status", "active"}),
+		Entry("Lt", djoemo.Attribute("Age").Lt(18), "$ < ?", []any{"Age", 18}),
+		Entry("Ge", djoemo.Attribute("Age").Ge(18), "$ >= ?", []any{"Age", 18}),
+		Entry("Between", djoemo.Attribute("Age").Between(18, 65), "$ BETWEEN ? AND ?", []any{"Age", 18, 65}),
+		Entry("In", djoemo.Attribute("Status").In("active", "new"), "$ IN (?, ?)", []any{"Status", "active", "new"}),
+		Entry("BeginsWith", djoemo.Attribute("Email").BeginsWith("admin"), "begins_with($, ?)", []any{"Email", "admin"}),
+		Entry("Contains", djoemo.Attribute("Tags").Contains("vip"), "contains($, ?)", []any{"Tags", "vip"}),
+		Entry("Exists", djoemo.Attribute("Email").Exists(), "attribute_exists($)", []any{"Email"}),
+		Entry("NotExists", djoemo.Attribute("Version").NotExists(), "attribute_not_exists($)", []any{"Version"}),
+		Entry("Type", djoemo.Attribute("Meta").Type(djoemo.TypeMap), "attribute_type($, ?)", []any{"Meta", "M"}),
+		Entry("Size", djoemo.Attribute("Tags").Size().Gt(2), "size($) > ?", []any{"Tags", 2}),
+		Entry("Size Between", djoemo.Attribute("Tags").Size().Between(1, 3), "size($) BETWEEN ? AND ?", []any{"Tags", 1, 3}),
+		Entry("nested path", djoemo.Attribute("Address.Lines[0].Street").Eq("main"), "$.$[0].$ = ?", []any{"Address", "Lines", "Street", "main"}),
+		Entry("Not", djoemo.Not(djoemo.Attribute("Email").Exists()), "NOT (attribute_exists($))", []any{"Email"}),
+		Entry("And of one condition", djoemo.And(djoemo.Attribute("Age").Lt(18), djoemo.Condition{}), "$ < ?", []any{"Age", 18}),
+		Entry("And/Or",
+			djoemo.Or(
+				djoemo.Attribute("Version").NotExists(),
+				djoemo.And(djoemo.Attribute("Version").Eq(3), djoemo.Attribute("Status").Ne("deleted")),
+			),
+			"(attribute_not_exists($)) OR (($ = ?) AND ($ <> ?))",
+			[]any{"Version", "Version", 3, "Status", "deleted"},
+		),
+	)
+
+	It("should be empty without conditions", func() {
+		Expect(djoemo.And().IsEmpty()).To(BeTrue())
+		Expect(djoemo.And().Err()).To(BeNil())
+	})
+
+	It("should be empty if an empty condition is negated", func() {
+		condition := djoemo.Not(djoemo.And())
+		Expect(condition.IsEmpty()).To(BeTrue())
+		Expect(condition.Expression()).To(BeEmpty())
+		Expect(condition.Err()).To(BeNil())
+	})
+
+	It("should be invalid for In without values", func() {
+		condition := djoemo.Attribute("Status").In()
+		Expect(condition.IsEmpty()).To(BeFalse())
+		Expect(condition.Expression()).To(BeEmpty())
+		Expect(condition.Args()).To(BeEmpty())
+		Expect(condition.Err()).To(Equal(djoemo.ErrInvalidCondition))
+	})
+
+	It("should stay invalid when an invalid condition is combined", func() {
+		invalid := djoemo.Attribute("Status").In()
+		for _, condition := range []djoemo.Condition{
+			djoemo.Not(invalid),
+			djoemo.And(djoemo.Attribute("Age").Lt(18), invalid),
+			djoemo.Or(invalid, djoemo.Attribute("Age").Lt(18)),
+		} {
+			Expect(condition.Expression()).To(BeEmpty())
+			Expect(condition.Err()).To(Equal(djoemo.ErrInvalidCondition))
+		}
+	})
+
+	Describe("usage", func() {
+		const UserTableName = "UserTable"
+
+		var (
+			dMock       mock.DynamoMock
+			repository  djoemo.RepositoryInterface
+			metricsMock *mock.MockMetricsInterface
+		)
+
+		BeforeEach(func() {
+			mockCtrl := gomock.NewController(GinkgoT())
+			dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+			dMock = mock.NewDynamoMock(dAPIMock)
+			metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+			repository = djoemo.NewRepository(dAPIMock)
+			repository.WithMetrics(metricsMock)
+		})
+
+		It("should be accepted as condition of an update", func() {
+			key := djoemo.Key().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid")
+			condition := djoemo.Or(djoemo.Attribute("Version").NotExists(), djoemo.Attribute("Version").Eq(3))
+
+			dMock.DynamoDBAPIMock.EXPECT().
+				PutItemWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.PutItemInput, _ ...any) (*dynamodb.PutItemOutput, error) {
+					Expect(*input.ConditionExpression).To(Equal("(attribute_not_exists(#sKZSXE43JN5XA)) OR (#sKZSXE43JN5XA = :v0)"))
+					Expect(input.ExpressionAttributeNames).To(Equal(map[string]*string{"#sKZSXE43JN5XA": aws.String("Version")}))
+					Expect(input.ExpressionAttributeValues).To(Equal(map[string]*dynamodb.AttributeValue{":v0": {N: aws.String("3")}}))
+					return &dynamodb.PutItemOutput{}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpUpdate, key, gomock.Any(), true)
+
+			updated, err := repository.ConditionalUpdateWithContext(context.Background(), key, map[string]any{"UUID": "uuid"}, condition.Expression(), condition.Args()...)
+			Expect(err).To(BeNil())
+			Expect(updated).To(BeTrue())
+		})
+
+		It("should be accepted as filter of a scan count", func() {
+			key := djoemo.Key().WithTableName(UserTableName)
+			condition := djoemo.And(djoemo.Attribute("Status").In("active", "new"), djoemo.Attribute("Tags").Size().Ge(1))
+
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					Expect(*input.FilterExpression).To(Equal("(#sKN2GC5DVOM IN (:v0, :v1)) AND (size(#sKRQWO4Y) >= :v2)"))
+					Expect(input.ExpressionAttributeNames).To(Equal(map[string]*string{
+						"#sKN2GC5DVOM": aws.String("Status"),
+						"#sKRQWO4Y":    aws.String("Tags"),
+					}))
+					return &dynamodb.ScanOutput{Count: aws.Int64(2)}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			count, err := repository.ScanCountWithContext(context.Background(), key, condition.Expression(), condition.Args()...)
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(2))
+		})
+	})
+})
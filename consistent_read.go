@@ -0,0 +1,41 @@
+package djoemo
+
+import (
+	"context"
+)
+
+type consistentReadContextKey int
+
+const consistentReadCtxKey consistentReadContextKey = iota
+
+// WithConsistentRead enables or disables strongly consistent reads for the read operations called with the returned context;
+// it overrides the default of the table set with WithConsistentReadTables
+func WithConsistentRead(ctx context.Context, consistent bool) context.Context {
+	return context.WithValue(ctx, consistentReadCtxKey, consistent)
+}
+
+func consistentReadFromContext(ctx context.Context) (consistent bool, ok bool) {
+	consistent, ok = ctx.Value(consistentReadCtxKey).(bool)
+	return consistent, ok
+}
+
+// isConsistentRead returns true if the read of key is strongly consistent; the key option takes precedence
+// over the context, which takes precedence over the default of the table
+func isConsistentRead(ctx context.Context, key KeyInterface, consistentReadTables map[string]bool) bool {
+	if keyConsistentRead(key) {
+		return true
+	}
+	if consistent, ok := consistentReadFromContext(ctx); ok {
+		return consistent
+	}
+	return consistentReadTables[key.TableName()]
+}
+
+// validateGlobalIndexRead returns ErrConsistentReadNotSupported if a strongly consistent read of key was requested,
+// since global secondary indexes do not support them
+func validateGlobalIndexRead(ctx context.Context, key KeyInterface) error {
+	if isConsistentRead(ctx, key, nil) {
+		return ErrConsistentReadNotSupported
+	}
+	return nil
+}
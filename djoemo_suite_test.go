@@ -1,9 +1,19 @@
 package djoemo_test
 
 import (
+	"context"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -32,3 +42,44 @@ type Profile struct {
 	CreatedAt time.Time
 	TraceID   string
 }
+
+// userItem returns the item of the user with the given uuid; attributes are pairs of the name and the string value of further attributes
+func userItem(uuid string, attributes ...string) map[string]*dynamodb.AttributeValue {
+	item := map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String(uuid)}}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		item[attributes[i]] = &dynamodb.AttributeValue{S: aws.String(attributes[i+1])}
+	}
+	return item
+}
+
+// expectScanPages answers the scans with pages of users with the given uuids, every page but the last ends with the key of its last item
+// and a scan with an exclusive start key reads the page after it; "{segment}" in a uuid is replaced by the segment of a parallel scan.
+// check is called with every input if it is not nil
+func expectScanPages(dMock mock.DynamoMock, check func(input *dynamodb.ScanInput), pages ...[]string) {
+	dMock.DynamoDBAPIMock.EXPECT().
+		ScanWithContext(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+			if check != nil {
+				check(input)
+			}
+			segment := strconv.FormatInt(aws.Int64Value(input.Segment), 10)
+			items := make([][]map[string]*dynamodb.AttributeValue, len(pages))
+			for i, uuids := range pages {
+				for _, uuid := range uuids {
+					items[i] = append(items[i], userItem(strings.ReplaceAll(uuid, "{segment}", segment)))
+				}
+			}
+
+			page := 0
+			if input.ExclusiveStartKey != nil {
+				page = slices.IndexFunc(items, func(pageItems []map[string]*dynamodb.AttributeValue) bool {
+					return reflect.DeepEqual(pageItems[len(pageItems)-1], input.ExclusiveStartKey)
+				}) + 1
+			}
+			output := &dynamodb.ScanOutput{Items: items[page]}
+			if page < len(items)-1 {
+				output.LastEvaluatedKey = items[page][len(items[page])-1]
+			}
+			return output, nil
+		}).AnyTimes()
+}
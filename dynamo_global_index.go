@@ -2,30 +2,17 @@ package djoemo
 
 import (
 	"context"
-	"errors"
 	"reflect"
-	"time"
+	"slices"
 
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/guregu/dynamo"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-// GlobalIndex models a global secondary index used in a query
+// GlobalIndex models a global secondary index used in a query; it does not support strongly consistent reads
 type GlobalIndex struct {
-	name         string
-	dynamoClient *dynamo.DB
-	log          LogInterface
-	metrics      *Metrics
-}
-
-// WithLog enables logging; it accepts LogInterface as logger
-func (gi *GlobalIndex) WithLog(log LogInterface) {
-	gi.log = log
-}
-
-// WithMetrics enables metrics; it accepts MetricsInterface as metrics publisher
-func (gi *GlobalIndex) WithMetrics(metricsInterface MetricsInterface) {
-	gi.metrics.Add(metricsInterface)
+	secondaryIndex
 }
 
 // WithPrometheusMetrics enables prometheus metrics with the given config
@@ -35,137 +22,124 @@ func (gi *GlobalIndex) WithPrometheusMetrics(registry *prometheus.Registry, cfg
 	return gi
 }
 
-// GetItemWithContext item; it needs a key interface that is used to get the table name, hash key, and the range key if it exists; output will be contained in item; context is optional param, which used to enable log with context
-func (gi GlobalIndex) GetItemWithContext(ctx context.Context, key KeyInterface, item any) (bool, error) {
+// ScanIteratorWithContext returns an iterator for the items of the index; searchLimit is the evaluation limit, the number of items
+// read per request before a filter is applied, 0 reads pages of up to 1MB. The iterator continues with the next page until all
+// items are read or the result limit set with WithScanLimit is reached. Only the projection of key is read, the filter set with
+// WithScanFilter restricts the items. The scan resumes from the checkpoint set with WithScanCheckpoint or stored in the store
+// set with WithScanCheckpointStore
+// returns an error if the table name, the filter or the checkpoint is invalid
+func (gi GlobalIndex) ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64, opts ...ScanOption) (ScanIteratorInterface, error) {
 	var err error
-	defer gi.recordMetrics(ctx, OpRead, key, &err)()
+	cc := gi.metrics.consumedCapacity()
+	itrMetrics := newIterationMetrics(withIndexLabel(ctx, gi.name), gi.metrics, OpRead, key, cc)
+	defer itrMetrics.creationFailed(&err)
 
-	if err = isValidKey(key); err != nil {
-		return false, err
+	if err = isValidTableName(key); err != nil {
+		return nil, err
+	}
+	if err = validateGlobalIndexRead(ctx, key); err != nil {
+		return nil, err
 	}
 
-	err = buildTableKeyCondition(gi.table(key.TableName()), key).Index(gi.name).OneWithContext(ctx, item)
-	if err != nil {
-		if errors.Is(err, dynamo.ErrNotFound) {
-			gi.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
-			return false, nil
-		}
-
-		return false, err
+	var itr *Iterator
+	if itr, err = newScanIterator(ctx, gi.dynamoClient.Client(), key, gi.name, searchLimit, false, newScanOptions(opts), cc); err != nil {
+		return nil, err
 	}
+	itr.metrics = itrMetrics
 
-	return true, nil
+	return itr, nil
 }
 
-// GetItemsWithContext queries multiple items by key (hash key) and returns it in the slice of items items
-func (gi GlobalIndex) GetItemsWithContext(ctx context.Context, key KeyInterface, items any) (bool, error) {
+// QueryHydratedWithContext queries the index and reads the full items from the table, for indexes that do not project all attributes;
+// it accepts a query interface like QueryWithContext and the table of the index, whose key names are used to derive the table keys
+// of the index items. The items are batch got in chunks and given in items in the order of the index; the projection of the query
+// applies to the items of the table, items deleted after the index was queried are skipped. The index is read eventually consistent,
+// the items of the table are read strongly consistent if the query, the context or the default of the table requests it
+// returns ErrInvalidTableName if the table is not the table of the query, returns error in case of error
+func (gi GlobalIndex) QueryHydratedWithContext(ctx context.Context, query QueryInterface, table TableSpec, items any) error {
 	var err error
-	defer gi.recordMetrics(ctx, OpRead, key, &err)()
+	cc := gi.metrics.consumedCapacity()
+	defer gi.recordMetrics(ctx, OpRead, query, &err, cc)()
 
-	if err = isValidKey(key); err != nil {
-		return false, err
+	if !IsPointerOFSlice(items) {
+		err = ErrInvalidPointerSliceType
+		return err
 	}
-
-	err = gi.table(key.TableName()).Get(*key.HashKeyName(), key.HashKey()).Index(gi.name).AllWithContext(ctx, items)
-	if err != nil {
-		if errors.Is(err, dynamo.ErrNotFound) {
-			gi.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
-			return false, nil
-		}
-
-		return false, err
+	if err = isValidKey(query); err != nil {
+		return err
 	}
-
-	val := reflect.ValueOf(items)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+	// the index belongs to the table of the query, a table spec of another table would derive wrong keys
+	if table.TableName != "" && table.TableName != query.TableName() {
+		err = ErrInvalidTableName
+		return err
 	}
-
-	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
-		if val.Len() == 0 {
-			return false, nil
-		}
+	if table.HashKeyName == "" {
+		err = ErrInvalidHashKeyName
+		return err
 	}
 
-	return true, nil
-}
-
-// GetItemsWithRangeWithContext queries multiple items by key (hash key) and returns it in the slice of items respecting the range key
-func (gi GlobalIndex) GetItemsWithRangeWithContext(ctx context.Context, key KeyInterface, items any) (bool, error) {
-	var err error
-	defer gi.recordMetrics(ctx, OpRead, key, &err)()
-
-	if err = isValidKey(key); err != nil {
-		return false, err
+	keyNames := []string{table.HashKeyName}
+	if table.RangeKeyName != "" {
+		keyNames = append(keyNames, table.RangeKeyName)
 	}
 
-	err = buildTableKeyCondition(gi.table(key.TableName()), key).Index(gi.name).AllWithContext(ctx, items)
+	q, err := buildKeyQuery(gi.table(query.TableName()), gi.name, query, gi.pageTokenCodec)
 	if err != nil {
-		if errors.Is(err, dynamo.ErrNotFound) {
-			gi.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
-			return false, nil
-		}
-
-		return false, err
+		return err
 	}
+	q = limitQuery(q, query).Project(keyNames...).ConsumedCapacity(cc)
 
-	val := reflect.ValueOf(items)
-	if val.Kind() == reflect.Ptr {
-		val = val.Elem()
+	var indexItems []map[string]*dynamodb.AttributeValue
+	if err = q.AllWithContext(ctx, &indexItems); err != nil {
+		return err
 	}
 
-	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
-		if val.Len() == 0 {
-			return false, nil
+	keys := make([]map[string]*dynamodb.AttributeValue, len(indexItems))
+	for i, indexItem := range indexItems {
+		keys[i] = make(map[string]*dynamodb.AttributeValue, len(keyNames))
+		for _, name := range keyNames {
+			if indexItem[name] == nil {
+				err = ErrMissingTableKey
+				return err
+			}
+			keys[i][name] = indexItem[name]
 		}
 	}
 
-	return true, nil
-}
-
-func (gi GlobalIndex) table(tableName string) dynamo.Table {
-	return gi.dynamoClient.Table(tableName)
-}
-
-// QueryWithContext by query; it accepts a query interface that is used to get the table name, hash key and range key with its operator if it exists;
-// context which used to enable log with context, the output will be given in items
-// returns error in case of error
-func (gi GlobalIndex) QueryWithContext(ctx context.Context, query QueryInterface, item any) (err error) {
-	defer gi.recordMetrics(ctx, OpRead, query, &err)()
-
-	if !IsPointerOFSlice(item) {
-		return ErrInvalidPointerSliceType
-	}
-	if err = isValidKey(query); err != nil {
-		return err
-	}
-
-	q := gi.table(query.TableName()).Get(*query.HashKeyName(), query.HashKey()).Index(gi.name)
-
-	// by range
-	if query.RangeKeyName() != nil && query.RangeKey() != nil {
-		q = q.Range(*query.RangeKeyName(), dynamo.Operator(query.RangeOp()), query.RangeKey())
+	// the key attributes are projected as well, so the items can be matched with their keys
+	projection := keyProjection(query)
+	if len(projection) > 0 {
+		for _, name := range keyNames {
+			if !slices.Contains(projection, name) {
+				projection = append(slices.Clip(projection), name)
+			}
+		}
 	}
 
-	if limit := valueFromPtr(query.Limit()); limit > 0 {
-		q = q.Limit(limit)
+	// the index is read eventually consistent, a strongly consistent read applies to the items of the table
+	consistent := isConsistentRead(ctx, query, gi.consistentReadTables)
+	tableItems, err := batchGetRawItems(ctx, gi.dynamoClient, query.TableName(), keys, projection, consistent, cc)
+	if err != nil {
+		return err
 	}
 
-	if query.Descending() {
-		q = q.Order(dynamo.Descending)
+	itemsByKey := make(map[string]map[string]*dynamodb.AttributeValue, len(tableItems))
+	for _, item := range tableItems {
+		itemsByKey[itemKeyID(item, keyNames)] = item
 	}
 
-	err = q.AllWithContext(ctx, item)
-	if err != nil {
-		return err
+	slice := reflect.ValueOf(items).Elem()
+	for _, key := range keys {
+		item, found := itemsByKey[itemKeyID(key, keyNames)]
+		if !found {
+			continue
+		}
+		elem := reflect.New(slice.Type().Elem())
+		if err = dynamo.UnmarshalItem(item, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
 	}
 
 	return nil
 }
-
-func (gi GlobalIndex) recordMetrics(ctx context.Context, op string, key KeyInterface, err *error) func() {
-	start := time.Now()
-	return func() {
-		gi.metrics.Record(ctx, op, key, time.Since(start), isOpSuccess(err))
-	}
-}
@@ -35,4 +35,42 @@ type GlobalIndexInterface interface {
 	// context which used to enable log with context, the output will be given in items
 	// returns error in case of error
 	QueryWithContext(ctx context.Context, query QueryInterface, item interface{}) error
+
+	// QueryPageWithContext queries a single page; it accepts a query interface like QueryWithContext, the search limit of the query, or else its limit, is the page size
+	// and a page token set on the query resumes after the page it was returned for; the output will be given in items.
+	// With a filter expression a page may hold fewer items than the limit
+	// returns the token of the next page, empty if there are no more pages, returns an empty token and an error in case of error
+	QueryPageWithContext(ctx context.Context, query QueryInterface, items any) (nextPageToken string, err error)
+
+	// QueryIteratorWithContext returns an iterator for the items of a query; it accepts a query interface like QueryWithContext,
+	// pages are fetched while iterating, so items are not loaded into memory at once
+	// returns an error if the query is invalid, errors while iterating are returned by Err of the iterator
+	QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error)
+
+	// ScanIteratorWithContext returns an iterator for the items of the index; searchLimit is the evaluation limit, the number of items
+	// read per request before a filter is applied, 0 reads pages of up to 1MB. The iterator continues with the next page until all
+	// items are read or the result limit set with WithScanLimit is reached. Only the projection of key is read, the filter set with
+	// WithScanFilter restricts the items. The scan resumes from the checkpoint set with WithScanCheckpoint or stored in the store
+	// set with WithScanCheckpointStore
+	// returns an error if the table name, the filter or the checkpoint is invalid
+	ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64, opts ...ScanOption) (ScanIteratorInterface, error)
+
+	// CountWithContext counts the items of a query without reading them; it accepts a query interface like QueryWithContext,
+	// the filter expression of the query restricts the counted items. All pages are counted, the limits and the projection of the query are ignored
+	// returns the number of items, returns 0 and an error in case of error
+	CountWithContext(ctx context.Context, query QueryInterface) (int64, error)
+
+	// MultiQueryWithContext runs the queries of a multi query concurrently and merges their items in the order of the range key,
+	// ascending or descending like the queries; the items of all queries must hold the range key. The limit of the multi query
+	// caps the merged items and the items read by every query; the output will be given in items
+	// returns a MultiQueryError if some queries failed, items then holds the merged items of the other queries, returns an error in case of error
+	MultiQueryWithContext(ctx context.Context, multiQuery MultiQueryInterface, items any) error
+
+	// QueryHydratedWithContext queries the index and reads the full items from the table, for indexes that do not project all attributes;
+	// it accepts a query interface like QueryWithContext and the table of the index, whose key names are used to derive the table keys
+	// of the index items. The items are batch got in chunks and given in items in the order of the index; the projection of the query
+	// applies to the items of the table, items deleted after the index was queried are skipped. The index is read eventually consistent,
+	// the items of the table are read strongly consistent if the query, the context or the default of the table requests it
+	// returns ErrInvalidTableName if the table is not the table of the query, returns error in case of error
+	QueryHydratedWithContext(ctx context.Context, query QueryInterface, table TableSpec, items any) error
 }
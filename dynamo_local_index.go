@@ -0,0 +1,17 @@
+package djoemo
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LocalIndex models a local secondary index used in a query; it shares the hash key of its table and supports consistent reads
+type LocalIndex struct {
+	secondaryIndex
+}
+
+// WithPrometheusMetrics enables prometheus metrics with the given config
+func (li *LocalIndex) WithPrometheusMetrics(registry *prometheus.Registry, cfg *PrometheusConfig) LocalIndexInterface {
+	prommetrics := NewPrometheusMetrics(registry, cfg)
+	li.metrics.Add(prommetrics)
+	return li
+}
@@ -0,0 +1,61 @@
+package djoemo
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//go:generate mockgen -source=dynamo_local_index_interface.go -destination=./mock/dynamo_local_index_interface.go -package=mock .
+
+// LocalIndexInterface provides an interface to read from a local secondary index; reads can be strongly consistent
+type LocalIndexInterface interface {
+	// WithLog enables logging; it accepts LogInterface as logger
+	WithLog(log LogInterface)
+
+	// WithMetrics enables metrics; it accepts MetricsInterface as metrics publisher
+	WithMetrics(metricsInterface MetricsInterface)
+
+	// WithPrometheusMetrics enables prometheus metrics with the given config
+	WithPrometheusMetrics(registry *prometheus.Registry, cfg *PrometheusConfig) LocalIndexInterface
+
+	// GetItemWithContext get item from index; it accepts a key interface that is used to get the table name, hash key and range key if it exists;
+	// context which used to enable log with context; the output will be given in item
+	// returns true if item is found, returns false and nil if no item found, returns false and an error in case of error
+	GetItemWithContext(ctx context.Context, key KeyInterface, item interface{}) (bool, error)
+
+	// GetItemsWithContext by key from index; it accepts a key interface that is used to get the table name, hash key and range key if it exists;
+	// context which used to enable log with context, the output will be given in items
+	// returns true if items are found, returns false and nil if no items found, returns false and error in case of error
+	GetItemsWithContext(ctx context.Context, key KeyInterface, items interface{}) (bool, error)
+
+	// GetItemsWithRangeWithContext same as GetItemsWithContext, but also respects range key
+	GetItemsWithRangeWithContext(ctx context.Context, key KeyInterface, items interface{}) (bool, error)
+
+	// QueryWithContext by query; it accepts a query interface that is used to get the table name, hash key and range key with its operator if it exists;
+	// context which used to enable log with context, the output will be given in items
+	// returns error in case of error
+	QueryWithContext(ctx context.Context, query QueryInterface, item interface{}) error
+
+	// QueryPageWithContext queries a single page; it accepts a query interface like QueryWithContext, the search limit of the query, or else its limit, is the page size
+	// and a page token set on the query resumes after the page it was returned for; the output will be given in items.
+	// With a filter expression a page may hold fewer items than the limit
+	// returns the token of the next page, empty if there are no more pages, returns an empty token and an error in case of error
+	QueryPageWithContext(ctx context.Context, query QueryInterface, items any) (nextPageToken string, err error)
+
+	// QueryIteratorWithContext returns an iterator for the items of a query; it accepts a query interface like QueryWithContext,
+	// pages are fetched while iterating, so items are not loaded into memory at once
+	// returns an error if the query is invalid, errors while iterating are returned by Err of the iterator
+	QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error)
+
+	// CountWithContext counts the items of a query without reading them; it accepts a query interface like QueryWithContext,
+	// the filter expression of the query restricts the counted items. All pages are counted, the limits and the projection of the query are ignored
+	// returns the number of items, returns 0 and an error in case of error
+	CountWithContext(ctx context.Context, query QueryInterface) (int64, error)
+
+	// MultiQueryWithContext runs the queries of a multi query concurrently and merges their items in the order of the range key,
+	// ascending or descending like the queries; the items of all queries must hold the range key. The limit of the multi query
+	// caps the merged items and the items read by every query; the output will be given in items
+	// returns a MultiQueryError if some queries failed, items then holds the merged items of the other queries, returns an error in case of error
+	MultiQueryWithContext(ctx context.Context, multiQuery MultiQueryInterface, items any) error
+}
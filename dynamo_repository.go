@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"slices"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,17 +18,22 @@ import (
 
 // Repository facade for github.com/guregu/djoemo
 type Repository struct {
-	dynamoClient *dynamo.DB
-	log          LogInterface
-	metrics      *Metrics
+	dynamoClient   *dynamo.DB
+	log            LogInterface
+	metrics        *Metrics
+	pageTokenCodec PageTokenCodecInterface
+	// consistentReadTables are the tables that are read strongly consistent by default
+	consistentReadTables map[string]bool
 }
 
 // NewRepository factory method for djoemo repository
 func NewRepository(dynamoClient dynamodbiface.DynamoDBAPI) RepositoryInterface {
 	return &Repository{
-		dynamoClient: dynamo.NewFromIface(dynamoClient),
-		log:          NewNopLog(),
-		metrics:      &Metrics{},
+		dynamoClient:         dynamo.NewFromIface(dynamoClient),
+		log:                  NewNopLog(),
+		metrics:              &Metrics{},
+		pageTokenCodec:       plainPageTokenCodec{},
+		consistentReadTables: make(map[string]bool),
 	}
 }
 
@@ -47,18 +54,40 @@ func (repository *Repository) WithPrometheusMetrics(registry *prometheus.Registr
 	return repository
 }
 
+// WithPageTokenCodec sets the codec of the page tokens of QueryPageWithContext; tokens are only encoded, not signed, by default.
+// A nil codec restores the default; indexes use the codec of the repository at the time they are created
+func (repository *Repository) WithPageTokenCodec(codec PageTokenCodecInterface) RepositoryInterface {
+	repository.pageTokenCodec = codec
+	return repository
+}
+
+// WithConsistentReadTables makes strongly consistent reads the default for the given tables;
+// it can be overridden per call with WithConsistentRead
+func (repository *Repository) WithConsistentReadTables(tableNames ...string) {
+	if repository.consistentReadTables == nil {
+		repository.consistentReadTables = make(map[string]bool)
+	}
+	for _, tableName := range tableNames {
+		repository.consistentReadTables[tableName] = true
+	}
+}
+
 // GetItemWithContext get item; it accepts a key interface that is used to get the table name, hash key and range key if it exists;
 // context which used to enable log with context; the output will be given in item
 // returns true if item is found, returns false and nil if no item found, returns false and an error in case of error
 func (repository Repository) GetItemWithContext(ctx context.Context, key KeyInterface, item interface{}) (bool, error) {
 	var err error
-	defer repository.recordMetrics(ctx, OpRead, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, key, &err, cc)()
 
 	if err = isValidKey(key); err != nil {
 		return false, err
 	}
 
-	err = buildTableKeyCondition(repository.table(key.TableName()), key).OneWithContext(ctx, item)
+	err = projectQuery(buildTableKeyCondition(repository.table(key.TableName()), key), key).
+		Consistent(isConsistentRead(ctx, key, repository.consistentReadTables)).
+		ConsumedCapacity(cc).
+		OneWithContext(ctx, item)
 	if err != nil {
 		if errors.Is(err, dynamo.ErrNotFound) {
 			repository.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
@@ -75,13 +104,14 @@ func (repository Repository) GetItemWithContext(ctx context.Context, key KeyInte
 // returns error in case of error
 func (repository Repository) SaveItemWithContext(ctx context.Context, key KeyInterface, item interface{}) error {
 	var err error
-	defer repository.recordMetrics(ctx, OpCommit, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpCommit, key, &err, cc)()
 
 	if err = isValidKey(key); err != nil {
 		return err
 	}
 
-	err = repository.table(key.TableName()).Put(item).RunWithContext(ctx)
+	err = repository.table(key.TableName()).Put(item).ConsumedCapacity(cc).RunWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -94,7 +124,8 @@ func (repository Repository) SaveItemWithContext(ctx context.Context, key KeyInt
 // returns error in case of error
 func (repository Repository) UpdateWithContext(ctx context.Context, expression UpdateExpression, key KeyInterface, values map[string]interface{}) error {
 	var err error
-	defer repository.recordMetrics(ctx, OpUpdate, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpUpdate, key, &err, cc)()
 
 	if err = isValidKey(key); err != nil {
 		return err
@@ -130,7 +161,7 @@ func (repository Repository) UpdateWithContext(ctx context.Context, expression U
 		}
 	}
 
-	err = update.RunWithContext(ctx)
+	err = update.ConsumedCapacity(cc).RunWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -193,14 +224,15 @@ func (repository Repository) UpdateWithUpdateExpressions(
 	updateExpressions UpdateExpressions,
 ) error {
 	var err error
-	defer repository.recordMetrics(ctx, OpUpdate, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpUpdate, key, &err, cc)()
 
 	update, err := repository.prepareUpdateWithUpdateExpressions(ctx, key, updateExpressions)
 	if err != nil {
 		return err
 	}
 
-	err = update.RunWithContext(ctx)
+	err = update.ConsumedCapacity(cc).RunWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -218,14 +250,15 @@ func (repository Repository) UpdateWithUpdateExpressionsAndReturnValue(
 	updateExpressions UpdateExpressions,
 ) error {
 	var err error
-	defer repository.recordMetrics(ctx, OpUpdate, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpUpdate, key, &err, cc)()
 
 	update, err := repository.prepareUpdateWithUpdateExpressions(ctx, key, updateExpressions)
 	if err != nil {
 		return err
 	}
 
-	err = update.ValueWithContext(ctx, item)
+	err = update.ConsumedCapacity(cc).ValueWithContext(ctx, item)
 	if err != nil {
 		return err
 	}
@@ -245,14 +278,15 @@ func (repository Repository) ConditionalUpdateWithUpdateExpressionsAndReturnValu
 	conditionArgs ...interface{},
 ) (bool, error) {
 	var err error
-	defer repository.recordMetrics(ctx, OpUpdate, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpUpdate, key, &err, cc)()
 
 	update, err := repository.prepareUpdateWithUpdateExpressions(ctx, key, updateExpressions)
 	if err != nil {
 		return false, err
 	}
 
-	update = update.If(conditionExpression, conditionArgs...)
+	update = update.If(conditionExpression, conditionArgs...).ConsumedCapacity(cc)
 
 	err = update.ValueWithContext(ctx, item)
 	if err != nil {
@@ -271,7 +305,8 @@ func (repository Repository) ConditionalUpdateWithUpdateExpressionsAndReturnValu
 // returns error in case of error
 func (repository Repository) DeleteItemWithContext(ctx context.Context, key KeyInterface) error {
 	var err error
-	defer repository.recordMetrics(ctx, OpDelete, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpDelete, key, &err, cc)()
 
 	if err = isValidKey(key); err != nil {
 		return err
@@ -284,7 +319,7 @@ func (repository Repository) DeleteItemWithContext(ctx context.Context, key KeyI
 		delete = delete.Range(*key.RangeKeyName(), key.RangeKey())
 	}
 
-	err = delete.RunWithContext(ctx)
+	err = delete.ConsumedCapacity(cc).RunWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -296,7 +331,8 @@ func (repository Repository) DeleteItemWithContext(ctx context.Context, key KeyI
 // returns error in case of error
 func (repository Repository) SaveItemsWithContext(ctx context.Context, key KeyInterface, items interface{}) error {
 	var err error
-	defer repository.recordMetrics(ctx, OpCommit, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpCommit, key, &err, cc)()
 
 	if err = isValidKey(key); err != nil {
 		return err
@@ -314,7 +350,21 @@ func (repository Repository) SaveItemsWithContext(ctx context.Context, key KeyIn
 		return err
 	}
 
-	_, err = batch.Write().Put(itemSlice...).RunWithContext(ctx)
+	// limited writes are sent by the client directly, see limitedRequest
+	if capacityLimiterFromContext(ctx).writes() != nil {
+		requests := make([]*dynamodb.WriteRequest, len(itemSlice))
+		for i, item := range itemSlice {
+			var dItem map[string]*dynamodb.AttributeValue
+			if dItem, err = dynamo.MarshalItem(item); err != nil {
+				return err
+			}
+			requests[i] = &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: dItem}}
+		}
+		err = batchWriteRawItems(ctx, repository.dynamoClient, key.TableName(), requests, cc)
+		return err
+	}
+
+	_, err = batch.Write().Put(itemSlice...).ConsumedCapacity(cc).RunWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -326,7 +376,8 @@ func (repository Repository) SaveItemsWithContext(ctx context.Context, key KeyIn
 // returns error in case of error
 func (repository Repository) DeleteItemsWithContext(ctx context.Context, keys []KeyInterface) error {
 	var err error
-	defer repository.recordMultipleMetrics(ctx, OpDelete, keys, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMultipleMetrics(ctx, OpDelete, keys, &err, cc)()
 
 	if len(keys) == 0 {
 		return nil
@@ -344,12 +395,27 @@ func (repository Repository) DeleteItemsWithContext(ctx context.Context, keys []
 		batch = repository.table(keys[0].TableName()).Batch(*keys[0].HashKeyName(), *keys[0].RangeKeyName())
 	}
 
+	// limited deletes are sent by the client directly, see limitedRequest
+	if capacityLimiterFromContext(ctx).writes() != nil {
+		withRange := keys[0].RangeKeyName() != nil
+		requests := make([]*dynamodb.WriteRequest, len(keys))
+		for i, key := range keys {
+			var dKey map[string]*dynamodb.AttributeValue
+			if dKey, err = dynamoKey(key, withRange); err != nil {
+				return err
+			}
+			requests[i] = &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: dKey}}
+		}
+		err = batchWriteRawItems(ctx, repository.dynamoClient, keys[0].TableName(), requests, cc)
+		return err
+	}
+
 	dynamoKeys := make([]dynamo.Keyed, len(keys))
 	for i := 0; i < len(keys); i++ {
 		dynamoKeys[i] = dynamo.Keyed(keys[i])
 	}
 
-	_, err = batch.Write().Delete(dynamoKeys...).RunWithContext(ctx)
+	_, err = batch.Write().Delete(dynamoKeys...).ConsumedCapacity(cc).RunWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -362,13 +428,17 @@ func (repository Repository) DeleteItemsWithContext(ctx context.Context, keys []
 // returns true if items are found, returns false and nil if no items found, returns false and error in case of error
 func (repository Repository) GetItemsWithContext(ctx context.Context, key KeyInterface, items interface{}) (bool, error) {
 	var err error
-	defer repository.recordMetrics(ctx, OpRead, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, key, &err, cc)()
 
 	if err = isValidKey(key); err != nil {
 		return false, err
 	}
 
-	err = repository.table(key.TableName()).Get(*key.HashKeyName(), key.HashKey()).AllWithContext(ctx, items)
+	err = projectQuery(repository.table(key.TableName()).Get(*key.HashKeyName(), key.HashKey()), key).
+		Consistent(isConsistentRead(ctx, key, repository.consistentReadTables)).
+		ConsumedCapacity(cc).
+		AllWithContext(ctx, items)
 	if err != nil {
 		if errors.Is(err, dynamo.ErrNotFound) {
 			repository.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
@@ -396,7 +466,8 @@ func (repository Repository) GetItemsWithContext(ctx context.Context, key KeyInt
 // context which used to enable log with context, the output will be given in items
 // returns error in case of error
 func (repository Repository) QueryWithContext(ctx context.Context, query QueryInterface, item interface{}) (err error) {
-	defer repository.recordMetrics(ctx, OpRead, query, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, query, &err, cc)()
 
 	if !IsPointerOFSlice(item) {
 		return ErrInvalidPointerSliceType
@@ -405,33 +476,130 @@ func (repository Repository) QueryWithContext(ctx context.Context, query QueryIn
 		return err
 	}
 
-	q := repository.table(query.TableName()).Get(*query.HashKeyName(), query.HashKey())
+	q, err := buildKeyQuery(repository.table(query.TableName()), "", query, repository.tokenCodec())
+	if err != nil {
+		return err
+	}
+	q = projectQuery(q, query).Consistent(isConsistentRead(ctx, query, repository.consistentReadTables)).ConsumedCapacity(cc)
+
+	q = limitQuery(q, query)
 
-	// by range
-	if query.RangeKeyName() != nil && query.RangeKey() != nil {
-		q = q.Range(*query.RangeKeyName(), dynamo.Operator(query.RangeOp()), query.RangeKey())
+	err = q.AllWithContext(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// QueryPageWithContext queries a single page; it accepts a query interface like QueryWithContext, the search limit of the query, or else its limit, is the page size
+// and a page token set on the query resumes after the page it was returned for; the output will be given in items.
+// With a filter expression a page may hold fewer items than the limit
+// returns the token of the next page, empty if there are no more pages, returns an empty token and an error in case of error
+func (repository Repository) QueryPageWithContext(ctx context.Context, query QueryInterface, items any) (string, error) {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, query, &err, cc)()
+
+	if !IsPointerOFSlice(items) {
+		err = ErrInvalidPointerSliceType
+		return "", err
+	}
+	if err = isValidKey(query); err != nil {
+		return "", err
 	}
 
-	if limit := valueFromPtr(query.Limit()); limit > 0 {
-		q = q.Limit(limit)
+	q, err := buildKeyQuery(repository.table(query.TableName()), "", query, repository.tokenCodec())
+	if err != nil {
+		return "", err
 	}
+	q = projectQuery(q, query).Consistent(isConsistentRead(ctx, query, repository.consistentReadTables)).ConsumedCapacity(cc)
 
-	if query.Descending() {
-		q = q.Order(dynamo.Descending)
+	// the search limit stops the query after a single request, so the last evaluated key is the end of the page
+	if limit := pageSize(query); limit > 0 {
+		q = q.SearchLimit(limit)
 	}
 
-	err = q.AllWithContext(ctx, item)
+	lastEvaluatedKey, err := q.AllWithLastEvaluatedKeyContext(ctx, items)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	nextPageToken, err := encodePageToken(repository.tokenCodec(), query, "", lastEvaluatedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return nextPageToken, nil
+}
+
+// QueryIteratorWithContext returns an iterator for the items of a query; it accepts a query interface like QueryWithContext,
+// pages are fetched while iterating, so items are not loaded into memory at once
+// returns an error if the query is invalid, errors while iterating are returned by Err of the iterator
+func (repository Repository) QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error) {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	itrMetrics := newIterationMetrics(ctx, repository.metrics, OpRead, query, cc)
+	defer itrMetrics.creationFailed(&err)
+
+	if err = isValidKey(query); err != nil {
+		return nil, err
+	}
+
+	q, err := buildKeyQuery(repository.table(query.TableName()), "", query, repository.tokenCodec())
+	if err != nil {
+		return nil, err
+	}
+	q = projectQuery(q, query).Consistent(isConsistentRead(ctx, query, repository.consistentReadTables)).ConsumedCapacity(cc)
+
+	q = limitQuery(q, query)
+
+	return &QueryIterator{
+		iterator: q.Iter(),
+		ctx:      ctx,
+		metrics:  itrMetrics,
+	}, nil
+}
+
+// CountWithContext counts the items of a query without reading them; it accepts a query interface like QueryWithContext,
+// the filter expression of the query restricts the counted items. All pages are counted, the limits and the projection of the query are ignored
+// returns the number of items, returns 0 and an error in case of error
+func (repository Repository) CountWithContext(ctx context.Context, query QueryInterface) (int64, error) {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, query, &err, cc)()
+
+	if err = isValidKey(query); err != nil {
+		return 0, err
+	}
+
+	q, err := buildKeyQuery(repository.table(query.TableName()), "", query, repository.tokenCodec())
+	if err != nil {
+		return 0, err
+	}
+	q = q.Consistent(isConsistentRead(ctx, query, repository.consistentReadTables)).ConsumedCapacity(cc)
+
+	count, err := q.CountWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MultiQueryWithContext runs the queries of a multi query concurrently and merges their items in the order of the range key,
+// ascending or descending like the queries; the items of all queries must hold the range key. The limit of the multi query
+// caps the merged items and the items read by every query; the output will be given in items
+// returns a MultiQueryError if some queries failed, items then holds the merged items of the other queries, returns an error in case of error
+func (repository Repository) MultiQueryWithContext(ctx context.Context, multiQuery MultiQueryInterface, items any) error {
+	return runMultiQuery(ctx, multiQuery, items, repository.QueryWithContext)
 }
 
 // OptimisticLockSaveWithContext saves an item if the version attribute on the server matches the version of the object
 func (repository Repository) OptimisticLockSaveWithContext(ctx context.Context, key KeyInterface, item interface{}) (bool, error) {
 	var err error
-	defer repository.recordMetrics(ctx, OpCommit, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpCommit, key, &err, cc)()
 
 	model, isDjoemoModel := item.(ModelInterface)
 	if !isDjoemoModel {
@@ -439,11 +607,9 @@ func (repository Repository) OptimisticLockSaveWithContext(ctx context.Context,
 	}
 
 	currentVersion := model.GetVersion()
-	model.IncreaseVersion()
-	model.InitCreatedAt()
-	model.InitUpdatedAt()
+	touchModel(model)
 
-	update := repository.table(key.TableName()).Put(item).If("attribute_not_exists(Version) OR Version = ?", currentVersion)
+	update := repository.table(key.TableName()).Put(item).If(versionCondition, currentVersion).ConsumedCapacity(cc)
 
 	err = update.Run()
 	if err != nil {
@@ -460,9 +626,10 @@ func (repository Repository) OptimisticLockSaveWithContext(ctx context.Context,
 // ConditionalUpdateWithContext updates an item when the condition is met, otherwise the update will be rejected
 func (repository Repository) ConditionalUpdateWithContext(ctx context.Context, key KeyInterface, item interface{}, expression string, expressionArgs ...interface{}) (bool, error) {
 	var err error
-	defer repository.recordMetrics(ctx, OpUpdate, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpUpdate, key, &err, cc)()
 
-	update := repository.table(key.TableName()).Put(item).If(expression, expressionArgs...)
+	update := repository.table(key.TableName()).Put(item).If(expression, expressionArgs...).ConsumedCapacity(cc)
 
 	err = update.Run()
 	if err != nil {
@@ -476,13 +643,44 @@ func (repository Repository) ConditionalUpdateWithContext(ctx context.Context, k
 	return true, nil
 }
 
+// tokenCodec returns the codec of the page tokens, the plain codec if none is set
+func (repository Repository) tokenCodec() PageTokenCodecInterface {
+	if repository.pageTokenCodec == nil {
+		return plainPageTokenCodec{}
+	}
+	return repository.pageTokenCodec
+}
+
 // GIndex creates an index repository by name
 func (repository *Repository) GIndex(name string) GlobalIndexInterface {
-	return &GlobalIndex{
-		name:         name,
-		log:          repository.log,
-		dynamoClient: repository.dynamoClient,
-		metrics:      repository.metrics,
+	return &GlobalIndex{secondaryIndex{
+		name:           name,
+		log:            repository.log,
+		dynamoClient:   repository.dynamoClient,
+		metrics:        repository.metrics,
+		pageTokenCodec: repository.tokenCodec(),
+		// the index is not read strongly consistent, the tables are read strongly consistent by hydrated queries
+		consistentReadTables: repository.consistentReadTables,
+	}}
+}
+
+// LIndex creates a local index repository by name
+func (repository *Repository) LIndex(name string) LocalIndexInterface {
+	return &LocalIndex{secondaryIndex{
+		name:                 name,
+		log:                  repository.log,
+		dynamoClient:         repository.dynamoClient,
+		metrics:              repository.metrics,
+		pageTokenCodec:       repository.tokenCodec(),
+		consistentReads:      true,
+		consistentReadTables: repository.consistentReadTables,
+	}}
+}
+
+// UnitOfWork creates a unit of work that commits the changes of the registered models in one transaction
+func (repository *Repository) UnitOfWork() UnitOfWorkInterface {
+	return &UnitOfWork{
+		repository: repository,
 	}
 }
 
@@ -490,49 +688,171 @@ func (repository *Repository) table(tableName string) dynamo.Table {
 	return repository.dynamoClient.Table(tableName)
 }
 
-// ScanIteratorWithContext returns an instance of an Iterator that provides methods for scanning tables
-func (repository *Repository) ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64) (IteratorInterface, error) {
+// ScanIteratorWithContext returns an instance of an Iterator that provides methods for scanning tables; searchLimit is the evaluation limit,
+// the number of items read per request before a filter is applied, 0 reads pages of up to 1MB. The iterator continues with the next page
+// until all items are read or the result limit set with WithScanLimit is reached. Only the projection of key is read,
+// the filter set with WithScanFilter restricts the items. The scan resumes from the checkpoint set with WithScanCheckpoint or stored
+// in the store set with WithScanCheckpointStore, a checkpoint of a segment of a parallel scan resumes only that segment
+// returns an error if the table name, the filter or the checkpoint is invalid
+func (repository *Repository) ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64, opts ...ScanOption) (ScanIteratorInterface, error) {
 	var err error
-	defer repository.recordMetrics(ctx, OpRead, key, &err)()
+	cc := repository.metrics.consumedCapacity()
+	itrMetrics := newIterationMetrics(ctx, repository.metrics, OpRead, key, cc)
+	defer itrMetrics.creationFailed(&err)
 
 	if err = isValidTableName(key); err != nil {
 		return nil, err
 	}
 
-	scan := repository.table(key.TableName()).Scan()
-	pagingIterator := scan.Iter()
-
-	itr := &Iterator{
-		scan:        scan,
-		tableName:   key.TableName(),
-		searchLimit: searchLimit,
-		iterator:    pagingIterator,
-		ctx:         ctx,
+	consistent := isConsistentRead(ctx, key, repository.consistentReadTables)
+	var itr *Iterator
+	if itr, err = newScanIterator(ctx, repository.dynamoClient.Client(), key, "", searchLimit, consistent, newScanOptions(opts), cc); err != nil {
+		return nil, err
 	}
-	itr.scan.SearchLimit(searchLimit)
+	itr.metrics = itrMetrics
 
 	return itr, nil
 }
 
-// BatchGetItemsWithContext gets multiple items by their keys; all keys must refer to the same table.
-// out must be a pointer to a slice of your model type.
+// ScanCountWithContext counts the items of the table of key without reading them; the optional filter expression restricts
+// the counted items, every ? in it is replaced by a value and every $ by an attribute name from args, in order.
+// All pages are counted, so the whole table is read; the reads are taken from the read budget of the capacity limiter of the context
+// returns the number of items, returns 0 and an error in case of error
+func (repository *Repository) ScanCountWithContext(ctx context.Context, key KeyInterface, filter string, args ...any) (int64, error) {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, key, &err, cc)()
+
+	if err = isValidTableName(key); err != nil {
+		return 0, err
+	}
+
+	reads := capacityLimiterFromContext(ctx).reads()
+	input := &dynamodb.ScanInput{
+		TableName:              aws.String(key.TableName()),
+		Select:                 aws.String(dynamodb.SelectCount),
+		ConsistentRead:         aws.Bool(isConsistentRead(ctx, key, repository.consistentReadTables)),
+		ReturnConsumedCapacity: limitedReturnConsumedCapacity(cc, reads),
+	}
+	if filter != "" {
+		var expr expression
+		if expr, err = buildExpression(filter, args); err != nil {
+			return 0, err
+		}
+		input.FilterExpression = aws.String(expr.expression)
+		input.ExpressionAttributeNames = expr.expressionNames()
+		input.ExpressionAttributeValues = expr.expressionValues()
+	}
+
+	var count int64
+	for {
+		var output *dynamodb.ScanOutput
+		err = limitedRequest(ctx, reads, func() (float64, error) {
+			var err error
+			if output, err = repository.dynamoClient.Client().ScanWithContext(ctx, input); err != nil {
+				return 0, err
+			}
+			return capacityUnits(output.ConsumedCapacity), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		count += aws.Int64Value(output.Count)
+		addConsumedCapacity(cc, output.ConsumedCapacity)
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return count, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// ParallelScanWithContext scans the table of key in totalSegments segments that are read concurrently; handle is called
+// for every item, concurrently by the segments, so it must be safe for concurrent use. A segment stops at the first error
+// of a page or of handle, the other segments go on; cancelling the context stops all segments. Only the projection of key is read,
+// the filter set with WithScanFilter restricts the items and the progress of the segments is reported to the callback set with WithScanProgress.
+// The segments resume from the checkpoints set with WithScanCheckpoint or stored in the store set with WithScanCheckpointStore
+// returns a ParallelScanError if some segments failed, returns an error in case of error
+func (repository *Repository) ParallelScanWithContext(ctx context.Context, key KeyInterface, totalSegments int, handle func(ctx context.Context, item ScanItem) error, opts ...ScanOption) error {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMetrics(ctx, OpRead, key, &err, cc)()
+
+	if err = isValidTableName(key); err != nil {
+		return err
+	}
+	if totalSegments < 1 {
+		err = ErrInvalidTotalSegments
+		return err
+	}
+
+	options := newScanOptions(opts)
+	consistent := isConsistentRead(ctx, key, repository.consistentReadTables)
+	var input dynamodb.ScanInput
+	if input, err = newScanInput(key, "", 0, consistent, options, cc); err != nil {
+		return err
+	}
+	var checkpoints map[int]*ScanCheckpoint
+	if checkpoints, err = options.segmentCheckpoints(ctx, key.TableName(), totalSegments); err != nil {
+		return err
+	}
+
+	scan := &parallelScan{
+		client:        repository.dynamoClient.Client(),
+		input:         input,
+		totalSegments: totalSegments,
+		checkpoints:   checkpoints,
+		options:       options,
+		handle:        handle,
+		cc:            cc,
+	}
+	err = scan.run(ctx)
+	return err
+}
+
+// ParallelScanChannelWithContext scans the table of key like ParallelScanWithContext and sends the items to items;
+// items is closed when the scan is done, so the items can be ranged over while the scan runs
+// returns a ParallelScanError if some segments failed, returns an error in case of error
+func (repository *Repository) ParallelScanChannelWithContext(ctx context.Context, key KeyInterface, totalSegments int, items chan<- ScanItem, opts ...ScanOption) error {
+	defer close(items)
+
+	return repository.ParallelScanWithContext(ctx, key, totalSegments, func(ctx context.Context, item ScanItem) error {
+		select {
+		case items <- item:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, opts...)
+}
+
+// BatchGetItemsWithContext gets multiple items by their keys; all keys must refer to the same table and have the same projection,
+// the batch is read strongly consistent if any key is. out must be a pointer to a slice of your model type.
+// Returns ErrInvalidBatchProjection if the projections of the keys differ, an UnprocessedKeysError if dynamodb leaves keys unprocessed after all retries.
 // Returns (true, nil) if at least one item is found, (false, nil) if none found, or (false, err) on error.
 func (repository Repository) BatchGetItemsWithContext(ctx context.Context, keys []KeyInterface, out interface{}) (bool, error) {
 	var err error
-	defer repository.recordMultipleMetrics(ctx, OpRead, keys, &err)()
+	cc := repository.metrics.consumedCapacity()
+	defer repository.recordMultipleMetrics(ctx, OpRead, keys, &err, cc)()
 
 	if len(keys) == 0 {
 		return false, nil
 	}
 
-	// Validate keys and ensure they all point to the same table
+	// Validate keys and ensure they all point to the same table and read the same attributes
 	tableName := keys[0].TableName()
+	projection := keyProjection(keys[0])
 	for i := 0; i < len(keys); i++ {
 		if err = isValidKey(keys[i]); err != nil {
 			return false, err
 		}
 		if keys[i].TableName() != tableName {
-			return false, ErrInvalidBatchRequest
+			err = ErrInvalidBatchRequest
+			return false, err
+		}
+		if !slices.Equal(keyProjection(keys[i]), projection) {
+			err = ErrInvalidBatchProjection
+			return false, err
 		}
 	}
 
@@ -549,8 +869,17 @@ func (repository Repository) BatchGetItemsWithContext(ctx context.Context, keys
 		dKeys[i] = dynamo.Keyed(keys[i])
 	}
 
-	// Execute batch get
-	err = batch.Get(dKeys...).AllWithContext(ctx, out)
+	// the whole batch is read strongly consistent if any key requests it
+	consistent := slices.ContainsFunc(keys, func(key KeyInterface) bool {
+		return isConsistentRead(ctx, key, repository.consistentReadTables)
+	})
+
+	// Execute batch get; guregu/dynamo does not support projections for batch gets, so they and limited batch gets are sent by the client directly
+	if len(projection) > 0 || capacityLimiterFromContext(ctx).reads() != nil {
+		err = repository.batchGetRaw(ctx, keys, projection, consistent, out, cc)
+	} else {
+		err = batch.Get(dKeys...).Consistent(consistent).ConsumedCapacity(cc).AllWithContext(ctx, out)
+	}
 	if err != nil {
 		if errors.Is(err, dynamo.ErrNotFound) {
 			repository.log.WithContext(ctx).WithField(TableName, tableName).Info(ErrNoItemFound.Error())
@@ -574,21 +903,402 @@ func (repository Repository) BatchGetItemsWithContext(ctx context.Context, keys
 	return true, nil
 }
 
-func (repository Repository) recordMetrics(ctx context.Context, op string, key KeyInterface, err *error) func() {
+// batchGetRaw gets the items of keys, reading only the projected attributes if there is a projection, and appends them to out;
+// keys are requested in chunks of maxBatchGetKeys and unprocessed keys are retried with backoff; the consumed capacity is added to cc
+// returns dynamo.ErrNotFound if no item is found
+func (repository Repository) batchGetRaw(ctx context.Context, keys []KeyInterface, projection []string, consistent bool, out interface{}, cc *dynamo.ConsumedCapacity) error {
+	if !IsPointerOFSlice(out) {
+		return ErrInvalidPointerSliceType
+	}
+	slice := reflect.ValueOf(out).Elem()
+
+	withRange := keys[0].RangeKeyName() != nil && keys[0].RangeKey() != nil
+	dKeys := make([]map[string]*dynamodb.AttributeValue, len(keys))
+	for i, key := range keys {
+		dKey, err := dynamoKey(key, withRange)
+		if err != nil {
+			return err
+		}
+		dKeys[i] = dKey
+	}
+
+	items, err := batchGetRawItems(ctx, repository.dynamoClient, keys[0].TableName(), dKeys, projection, consistent, cc)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return dynamo.ErrNotFound
+	}
+
+	for _, item := range items {
+		elem := reflect.New(slice.Type().Elem())
+		if err = dynamo.UnmarshalItem(item, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+
+	return nil
+}
+
+// batchGetRawItems gets the items of keys of the table, reading only the projected attributes if there is a projection;
+// keys are requested in chunks of maxBatchGetKeys and unprocessed keys are retried with backoff up to maxUnprocessedRetries times,
+// then an UnprocessedKeysError with the keys that were not read is returned; the consumed capacity is added to cc and, if the context
+// has a capacity limiter, taken from its read budget. The items are returned in the order dynamodb returns them, which is not the order of keys
+func batchGetRawItems(ctx context.Context, db *dynamo.DB, tableName string, keys []map[string]*dynamodb.AttributeValue,
+	projection []string, consistent bool, cc *dynamo.ConsumedCapacity,
+) ([]map[string]*dynamodb.AttributeValue, error) {
+	reads := capacityLimiterFromContext(ctx).reads()
+	returnCapacity := limitedReturnConsumedCapacity(cc, reads)
+
+	var items []map[string]*dynamodb.AttributeValue
+	for start := 0; start < len(keys); start += maxBatchGetKeys {
+		end := min(start+maxBatchGetKeys, len(keys))
+
+		request := &dynamodb.KeysAndAttributes{
+			ConsistentRead: aws.Bool(consistent),
+			Keys:           keys[start:end],
+		}
+		if len(projection) > 0 {
+			expression, names := projectionExpression(projection)
+			request.ProjectionExpression = aws.String(expression)
+			request.ExpressionAttributeNames = names
+		}
+
+		requestItems := map[string]*dynamodb.KeysAndAttributes{tableName: request}
+		for attempt, backoff := 0, minBatchGetBackoff; ; attempt, backoff = attempt+1, min(backoff*2, maxBatchGetBackoff) {
+			var output *dynamodb.BatchGetItemOutput
+			err := limitedRequest(ctx, reads, func() (float64, error) {
+				var err error
+				output, err = db.Client().BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+					RequestItems:           requestItems,
+					ReturnConsumedCapacity: returnCapacity,
+				})
+				if err != nil {
+					return 0, err
+				}
+				return capacityUnits(output.ConsumedCapacity...), nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, consumed := range output.ConsumedCapacity {
+				addConsumedCapacity(cc, consumed)
+			}
+
+			items = append(items, output.Responses[tableName]...)
+
+			requestItems = output.UnprocessedKeys
+			if len(requestItems) == 0 {
+				break
+			}
+			// dynamodb leaves keys unprocessed if the provisioned throughput is exceeded
+			reads.throttled()
+			if attempt == maxUnprocessedRetries {
+				unprocessed := requestItems[tableName].Keys
+				return nil, &UnprocessedKeysError{TableName: tableName, Keys: append(slices.Clip(unprocessed), keys[end:]...)}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+	}
+
+	return items, nil
+}
+
+// batchWriteRawItems sends the write requests to the table in chunks of maxBatchWriteItems, unprocessed requests are retried
+// with backoff up to maxUnprocessedRetries times, then an UnprocessedItemsError with the requests that were not applied is returned; the consumed capacity is added to cc and, if the context has a capacity limiter, taken from its write budget
+func batchWriteRawItems(ctx context.Context, db *dynamo.DB, tableName string, requests []*dynamodb.WriteRequest, cc *dynamo.ConsumedCapacity) error {
+	writes := capacityLimiterFromContext(ctx).writes()
+	returnCapacity := limitedReturnConsumedCapacity(cc, writes)
+
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := min(start+maxBatchWriteItems, len(requests))
+
+		requestItems := map[string][]*dynamodb.WriteRequest{tableName: requests[start:end]}
+		for attempt, backoff := 0, minBatchGetBackoff; ; attempt, backoff = attempt+1, min(backoff*2, maxBatchGetBackoff) {
+			var output *dynamodb.BatchWriteItemOutput
+			err := limitedRequest(ctx, writes, func() (float64, error) {
+				var err error
+				output, err = db.Client().BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+					RequestItems:           requestItems,
+					ReturnConsumedCapacity: returnCapacity,
+				})
+				if err != nil {
+					return 0, err
+				}
+				return capacityUnits(output.ConsumedCapacity...), nil
+			})
+			if err != nil {
+				return err
+			}
+			for _, consumed := range output.ConsumedCapacity {
+				addConsumedCapacity(cc, consumed)
+			}
+
+			requestItems = output.UnprocessedItems
+			if len(requestItems) == 0 {
+				break
+			}
+			// dynamodb leaves items unprocessed if the provisioned throughput is exceeded
+			writes.throttled()
+			if attempt == maxUnprocessedRetries {
+				unprocessed := requestItems[tableName]
+				return &UnprocessedItemsError{TableName: tableName, Requests: append(slices.Clip(unprocessed), requests[end:]...)}
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+		}
+	}
+
+	return nil
+}
+
+// TransactWriteItemsWithContext commits all operations of the transaction atomically; the items may belong to different tables.
+// Either all operations are applied or none of them
+// returns error in case of error
+func (repository Repository) TransactWriteItemsWithContext(ctx context.Context, transaction TransactWriteInterface) error {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	capacities := newTableCapacities(repository.dynamoClient.Client(), cc)
+	defer repository.recordTransactionMetrics(ctx, OpTransaction, transactWriteKeys(transaction), &err, capacities)()
+
+	items := transaction.Items()
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx := repository.transactionClient(capacities).WriteTx().ConsumedCapacity(cc)
+	for _, item := range items {
+		if err = isValidKey(item.Key); err != nil {
+			return err
+		}
+		if err = repository.addTransactWriteItem(ctx, tx, item); err != nil {
+			return err
+		}
+	}
+
+	err = tx.RunWithContext(ctx)
+	if err != nil {
+		if awsError, ok := isTransactionCanceled(err); ok {
+			repository.log.WithContext(ctx).Info(dynamodb.ErrCodeTransactionCanceledException)
+			txErr := newTransactionCanceledError(awsError, transactWriteKeys(transaction))
+			if transaction.ReadCurrentItems() {
+				repository.readCurrentItems(ctx, txErr, cc, capacities)
+			}
+			err = txErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// readCurrentItems reads the current items of failed conditions into the cancellation reasons.
+// aws-sdk-go v1 drops the items dynamodb attaches to cancellation reasons, so they are read with a
+// strongly consistent read after the transaction was cancelled; the item may have changed in between.
+// The reads are taken from the read budget of the capacity limiter of the context and their capacity is added to the capacities of their tables
+func (repository Repository) readCurrentItems(ctx context.Context, txErr *TransactionCanceledError, cc *dynamo.ConsumedCapacity, capacities *tableCapacities) {
+	reads := capacityLimiterFromContext(ctx).reads()
+	for i, reason := range txErr.Reasons {
+		if !reason.ConditionFailed() || reason.Key == nil {
+			continue
+		}
+
+		dKey, err := dynamoKey(reason.Key, reason.Key.RangeKeyName() != nil && reason.Key.RangeKey() != nil)
+		if err != nil {
+			txErr.Reasons[i].CurrentItemErr = err
+			continue
+		}
+
+		var output *dynamodb.GetItemOutput
+		err = limitedRequest(ctx, reads, func() (float64, error) {
+			var err error
+			output, err = repository.dynamoClient.Client().GetItemWithContext(ctx, &dynamodb.GetItemInput{
+				TableName:              aws.String(reason.Key.TableName()),
+				Key:                    dKey,
+				ConsistentRead:         aws.Bool(true),
+				ReturnConsumedCapacity: limitedReturnConsumedCapacity(cc, reads),
+			})
+			if err != nil {
+				return 0, err
+			}
+			return capacityUnits(output.ConsumedCapacity), nil
+		})
+		if err != nil {
+			txErr.Reasons[i].CurrentItemErr = err
+			continue
+		}
+		capacities.add(output.ConsumedCapacity)
+		if len(output.Item) > 0 {
+			txErr.Reasons[i].CurrentItem = output.Item
+		}
+	}
+}
+
+// TransactGetItemsWithContext reads all items of the transaction from a single serializable snapshot; the items may belong to different tables.
+// Every found item is unmarshalled into its out pointer
+// returns the keys of the items that do not exist, returns nil and an error in case of error
+func (repository Repository) TransactGetItemsWithContext(ctx context.Context, transaction TransactGetInterface) ([]KeyInterface, error) {
+	var err error
+	cc := repository.metrics.consumedCapacity()
+	capacities := newTableCapacities(repository.dynamoClient.Client(), cc)
+	defer repository.recordTransactionMetrics(ctx, OpRead, transactGetKeys(transaction), &err, capacities)()
+
+	items := transaction.Items()
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	// raw items are collected first to tell missing items apart from found ones
+	rawItems := make([]map[string]*dynamodb.AttributeValue, len(items))
+	tx := repository.transactionClient(capacities).GetTx().ConsumedCapacity(cc)
+	for i, item := range items {
+		if err = isValidKey(item.Key); err != nil {
+			return nil, err
+		}
+		tx.GetOne(projectQuery(buildTableKeyCondition(repository.table(item.Key.TableName()), item.Key), item.Key), &rawItems[i])
+	}
+
+	err = tx.RunWithContext(ctx)
+	if err != nil && !errors.Is(err, dynamo.ErrNotFound) {
+		if awsError, ok := isTransactionCanceled(err); ok {
+			repository.log.WithContext(ctx).Info(dynamodb.ErrCodeTransactionCanceledException)
+			err = newTransactionCanceledError(awsError, transactGetKeys(transaction))
+		}
+
+		return nil, err
+	}
+
+	var missing []KeyInterface
+	for i, item := range items {
+		if rawItems[i] == nil {
+			repository.log.WithContext(ctx).WithField(TableName, item.Key.TableName()).Info(ErrNoItemFound.Error())
+			missing = append(missing, item.Key)
+			continue
+		}
+		if err = dynamo.UnmarshalItem(rawItems[i], item.Out); err != nil {
+			return nil, err
+		}
+	}
+
+	return missing, nil
+}
+
+func (repository Repository) addTransactWriteItem(ctx context.Context, tx *dynamo.WriteTx, item TransactWriteItem) error {
+	key := item.Key
+
+	switch item.Operation {
+	case TransactPut:
+		put := repository.table(key.TableName()).Put(item.Item)
+		if item.ConditionExpression != "" {
+			put = put.If(item.ConditionExpression, item.ConditionArgs...)
+		}
+		tx.Put(put)
+	case TransactUpdate:
+		update, err := repository.prepareUpdateWithUpdateExpressions(ctx, key, item.UpdateExpressions)
+		if err != nil {
+			return err
+		}
+		if item.ConditionExpression != "" {
+			update = update.If(item.ConditionExpression, item.ConditionArgs...)
+		}
+		tx.Update(update)
+	case TransactDelete:
+		// by hash
+		del := repository.table(key.TableName()).Delete(*key.HashKeyName(), key.HashKey())
+		// by range
+		if key.RangeKeyName() != nil && key.RangeKey() != nil {
+			del = del.Range(*key.RangeKeyName(), key.RangeKey())
+		}
+		if item.ConditionExpression != "" {
+			del = del.If(item.ConditionExpression, item.ConditionArgs...)
+		}
+		tx.Delete(del)
+	case TransactConditionCheck:
+		if item.ConditionExpression == "" {
+			return ErrMissingConditionExpression
+		}
+		// by hash
+		check := repository.table(key.TableName()).Check(*key.HashKeyName(), key.HashKey())
+		// by range
+		if key.RangeKeyName() != nil && key.RangeKey() != nil {
+			check = check.Range(*key.RangeKeyName(), key.RangeKey())
+		}
+		tx.Check(check.If(item.ConditionExpression, item.ConditionArgs...))
+	default:
+		return ErrInvalidTransactOperation
+	}
+
+	return nil
+}
+
+func (repository Repository) recordMetrics(ctx context.Context, op string, key KeyInterface, err *error, cc *dynamo.ConsumedCapacity) func() {
 	start := time.Now()
 	return func() {
 		repository.metrics.Record(ctx, op, key, time.Since(start), isOpSuccess(err))
+		repository.metrics.recordConsumedCapacity(ctx, op, key, cc)
+	}
+}
+
+// recordMultipleMetrics records the operation for every key and the consumed capacity once for the key of the table dynamodb reported it for
+func (repository Repository) recordMultipleMetrics(ctx context.Context, op string, keys []KeyInterface, err *error, cc *dynamo.ConsumedCapacity) func() {
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		for _, key := range keys {
+			repository.metrics.Record(ctx, op, key, duration, isOpSuccess(err))
+		}
+		if len(keys) > 0 {
+			repository.metrics.recordConsumedCapacity(ctx, op, capacityKey(keys, cc), cc)
+		}
 	}
 }
 
-func (repository Repository) recordMultipleMetrics(ctx context.Context, op string, keys []KeyInterface, err *error) func() {
+// recordTransactionMetrics records the operation for every key and the capacity consumed by every table for the first key of the table
+func (repository Repository) recordTransactionMetrics(ctx context.Context, op string, keys []KeyInterface, err *error, capacities *tableCapacities) func() {
 	start := time.Now()
 	return func() {
 		duration := time.Since(start)
+		recorded := make(map[string]bool, len(keys))
 		for _, key := range keys {
 			repository.metrics.Record(ctx, op, key, duration, isOpSuccess(err))
+			if !recorded[key.TableName()] {
+				recorded[key.TableName()] = true
+				repository.metrics.recordConsumedCapacity(ctx, op, key, capacities.of(key.TableName()))
+			}
+		}
+	}
+}
+
+// transactionClient returns the client that sends a transaction, it collects the capacities of the tables if they are recorded
+func (repository Repository) transactionClient(capacities *tableCapacities) *dynamo.DB {
+	if capacities == nil {
+		return repository.dynamoClient
+	}
+	return dynamo.NewFromIface(capacities)
+}
+
+// capacityKey returns the first key of the table of cc, keys[0] if there is none
+func capacityKey(keys []KeyInterface, cc *dynamo.ConsumedCapacity) KeyInterface {
+	if cc != nil {
+		for _, key := range keys {
+			if key.TableName() == cc.TableName {
+				return key
+			}
 		}
 	}
+
+	return keys[0]
 }
 
 func isOpSuccess(err *error) bool {
@@ -20,6 +20,14 @@ type RepositoryInterface interface {
 	// WithPrometheusMetrics enables prometheus metrics with the given config
 	WithPrometheusMetrics(registry *prometheus.Registry, cfg *PrometheusConfig) RepositoryInterface
 
+	// WithPageTokenCodec sets the codec of the page tokens of QueryPageWithContext; tokens are only encoded, not signed, by default.
+	// A nil codec restores the default; indexes use the codec of the repository at the time they are created
+	WithPageTokenCodec(codec PageTokenCodecInterface) RepositoryInterface
+
+	// WithConsistentReadTables makes strongly consistent reads the default for the given tables;
+	// it can be overridden per call with WithConsistentRead
+	WithConsistentReadTables(tableNames ...string)
+
 	// GetItemWithContext get item; it accepts a key interface that is used to get the table name, hash key and range key if it exists; the output will be given in item
 	// returns true if item is found, returns false and nil if no item found, returns false and an error in case of error
 	GetItemWithContext(ctx context.Context, key KeyInterface, item any) (bool, error)
@@ -53,11 +61,11 @@ type RepositoryInterface interface {
 	DeleteItemWithContext(ctx context.Context, key KeyInterface) error
 
 	// SaveItemsWithContext batch save a slice of items by key; it accepts key of item to be saved; item to be saved; context which used to enable log with context
-	// returns error in case of error
+	// returns error in case of error; with a capacity limiter the error is an UnprocessedItemsError if dynamodb leaves items unprocessed after all retries
 	SaveItemsWithContext(ctx context.Context, key KeyInterface, items any) error
 
 	// DeleteItemsWithContext deletes items matching the keys; it accepts array of keys to be deleted; context which used to enable log with context
-	// returns error in case of error
+	// returns error in case of error; with a capacity limiter the error is an UnprocessedItemsError if dynamodb leaves items unprocessed after all retries
 	DeleteItemsWithContext(ctx context.Context, key []KeyInterface) error
 
 	// GetItemsWithContext by key; it accepts key of item to get it; context which used to enable log with context
@@ -69,20 +77,83 @@ type RepositoryInterface interface {
 	// returns error in case of error
 	QueryWithContext(ctx context.Context, query QueryInterface, item any) error
 
+	// QueryPageWithContext queries a single page; it accepts a query interface like QueryWithContext, the search limit of the query, or else its limit, is the page size
+	// and a page token set on the query resumes after the page it was returned for; the output will be given in items.
+	// With a filter expression a page may hold fewer items than the limit
+	// returns the token of the next page, empty if there are no more pages, returns an empty token and an error in case of error
+	QueryPageWithContext(ctx context.Context, query QueryInterface, items any) (nextPageToken string, err error)
+
+	// QueryIteratorWithContext returns an iterator for the items of a query; it accepts a query interface like QueryWithContext,
+	// pages are fetched while iterating, so items are not loaded into memory at once
+	// returns an error if the query is invalid, errors while iterating are returned by Err of the iterator
+	QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error)
+
+	// CountWithContext counts the items of a query without reading them; it accepts a query interface like QueryWithContext,
+	// the filter expression of the query restricts the counted items. All pages are counted, the limits and the projection of the query are ignored
+	// returns the number of items, returns 0 and an error in case of error
+	CountWithContext(ctx context.Context, query QueryInterface) (int64, error)
+
+	// MultiQueryWithContext runs the queries of a multi query concurrently and merges their items in the order of the range key,
+	// ascending or descending like the queries; the items of all queries must hold the range key. The limit of the multi query
+	// caps the merged items and the items read by every query; the output will be given in items
+	// returns a MultiQueryError if some queries failed, items then holds the merged items of the other queries, returns an error in case of error
+	MultiQueryWithContext(ctx context.Context, multiQuery MultiQueryInterface, items any) error
+
 	// GIndex returns index repository
 	GIndex(name string) GlobalIndexInterface
 
+	// LIndex returns local index repository
+	LIndex(name string) LocalIndexInterface
+
+	// UnitOfWork returns a unit of work that commits the changes of the registered models in one transaction
+	UnitOfWork() UnitOfWorkInterface
+
 	// OptimisticLockSaveWithContext saves an item if the version attribute on the server matches the version of the object
 	OptimisticLockSaveWithContext(ctx context.Context, key KeyInterface, item any) (bool, error)
 
-	// ScanIteratorWithContext returns an instance of an iterator that provides methods to use for scanning tables
-	ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64) (IteratorInterface, error)
+	// ScanIteratorWithContext returns an instance of an iterator that provides methods to use for scanning tables; searchLimit is the evaluation limit,
+	// the number of items read per request before a filter is applied, 0 reads pages of up to 1MB. The iterator continues with the next page
+	// until all items are read or the result limit set with WithScanLimit is reached. Only the projection of key is read,
+	// the filter set with WithScanFilter restricts the items. The scan resumes from the checkpoint set with WithScanCheckpoint or stored
+	// in the store set with WithScanCheckpointStore, a checkpoint of a segment of a parallel scan resumes only that segment
+	// returns an error if the table name, the filter or the checkpoint is invalid
+	ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64, opts ...ScanOption) (ScanIteratorInterface, error)
+
+	// ScanCountWithContext counts the items of the table of key without reading them; the optional filter expression restricts
+	// the counted items, every ? in it is replaced by a value and every $ by an attribute name from args, in order.
+	// All pages are counted, so the whole table is read; the reads are taken from the read budget of the capacity limiter of the context
+	// returns the number of items, returns 0 and an error in case of error
+	ScanCountWithContext(ctx context.Context, key KeyInterface, filter string, args ...any) (int64, error)
+
+	// ParallelScanWithContext scans the table of key in totalSegments segments that are read concurrently; handle is called
+	// for every item, concurrently by the segments, so it must be safe for concurrent use. A segment stops at the first error
+	// of a page or of handle, the other segments go on; cancelling the context stops all segments. Only the projection of key is read,
+	// the filter set with WithScanFilter restricts the items and the progress of the segments is reported to the callback set with WithScanProgress.
+	// The segments resume from the checkpoints set with WithScanCheckpoint or stored in the store set with WithScanCheckpointStore
+	// returns a ParallelScanError if some segments failed, returns an error in case of error
+	ParallelScanWithContext(ctx context.Context, key KeyInterface, totalSegments int, handle func(ctx context.Context, item ScanItem) error, opts ...ScanOption) error
+
+	// ParallelScanChannelWithContext scans the table of key like ParallelScanWithContext and sends the items to items;
+	// items is closed when the scan is done, so the items can be ranged over while the scan runs
+	// returns a ParallelScanError if some segments failed, returns an error in case of error
+	ParallelScanChannelWithContext(ctx context.Context, key KeyInterface, totalSegments int, items chan<- ScanItem, opts ...ScanOption) error
 
 	// ConditionalUpdateWithContext updates an item if the passed expression and condition evaluates to true
 	ConditionalUpdateWithContext(ctx context.Context, key KeyInterface, item any, expression string, expressionArgs ...any) (bool, error)
 
-	// BatchGetItemsWithContext gets multiple items by their keys; it accepts a slice of keys (all from the same table)
-	// and fills out (pointer to a slice) with any found items.
-	// returns true if at least one item is found, returns false and nil if no items found, returns false and error in case of error
+	// BatchGetItemsWithContext gets multiple items by their keys; it accepts a slice of keys (all from the same table, with the same projection)
+	// and fills out (pointer to a slice) with any found items; the batch is read strongly consistent if any key is.
+	// returns true if at least one item is found, returns false and nil if no items found, returns false and error in case of error;
+	// the error is an UnprocessedKeysError if dynamodb leaves keys unprocessed after all retries
 	BatchGetItemsWithContext(ctx context.Context, keys []KeyInterface, out any) (bool, error)
+
+	// TransactWriteItemsWithContext commits all operations of the transaction (put, update, delete and condition check) atomically;
+	// the items may belong to different tables. Either all operations are applied or none of them
+	// returns error in case of error
+	TransactWriteItemsWithContext(ctx context.Context, transaction TransactWriteInterface) error
+
+	// TransactGetItemsWithContext reads all items of the transaction from a single serializable snapshot; the items may belong
+	// to different tables. Every found item is unmarshalled into its out pointer
+	// returns the keys of the items that do not exist, returns nil and an error in case of error
+	TransactGetItemsWithContext(ctx context.Context, transaction TransactGetInterface) (missing []KeyInterface, err error)
 }
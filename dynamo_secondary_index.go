@@ -0,0 +1,317 @@
+package djoemo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/guregu/dynamo"
+)
+
+// secondaryIndex implements the reads shared by global and local secondary indexes; only local secondary indexes
+// support strongly consistent reads
+type secondaryIndex struct {
+	name           string
+	dynamoClient   *dynamo.DB
+	log            LogInterface
+	metrics        *Metrics
+	pageTokenCodec PageTokenCodecInterface
+	// consistentReads is true if the index supports strongly consistent reads
+	consistentReads bool
+	// consistentReadTables are the tables that are read strongly consistent by default
+	consistentReadTables map[string]bool
+}
+
+// WithLog enables logging; it accepts LogInterface as logger
+func (si *secondaryIndex) WithLog(log LogInterface) {
+	si.log = log
+}
+
+// WithMetrics enables metrics; it accepts MetricsInterface as metrics publisher
+func (si *secondaryIndex) WithMetrics(metricsInterface MetricsInterface) {
+	si.metrics.Add(metricsInterface)
+}
+
+// GetItemWithContext item; it needs a key interface that is used to get the table name, hash key, and the range key if it exists; output will be contained in item; context is optional param, which used to enable log with context
+func (si secondaryIndex) GetItemWithContext(ctx context.Context, key KeyInterface, item any) (bool, error) {
+	var err error
+	cc := si.metrics.consumedCapacity()
+	defer si.recordMetrics(ctx, OpRead, key, &err, cc)()
+
+	if err = isValidKey(key); err != nil {
+		return false, err
+	}
+	consistent, err := si.isConsistentRead(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	err = projectQuery(buildTableKeyCondition(si.table(key.TableName()), key), key).Index(si.name).
+		Consistent(consistent).
+		ConsumedCapacity(cc).
+		OneWithContext(ctx, item)
+	if err != nil {
+		if errors.Is(err, dynamo.ErrNotFound) {
+			si.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetItemsWithContext queries multiple items by key (hash key) and returns it in the slice of items items
+func (si secondaryIndex) GetItemsWithContext(ctx context.Context, key KeyInterface, items any) (bool, error) {
+	var err error
+	cc := si.metrics.consumedCapacity()
+	defer si.recordMetrics(ctx, OpRead, key, &err, cc)()
+
+	if err = isValidKey(key); err != nil {
+		return false, err
+	}
+	consistent, err := si.isConsistentRead(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	err = projectQuery(si.table(key.TableName()).Get(*key.HashKeyName(), key.HashKey()), key).Index(si.name).
+		Consistent(consistent).
+		ConsumedCapacity(cc).
+		AllWithContext(ctx, items)
+	if err != nil {
+		if errors.Is(err, dynamo.ErrNotFound) {
+			si.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	val := reflect.ValueOf(items)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
+		if val.Len() == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// GetItemsWithRangeWithContext queries multiple items by key (hash key) and returns it in the slice of items respecting the range key
+func (si secondaryIndex) GetItemsWithRangeWithContext(ctx context.Context, key KeyInterface, items any) (bool, error) {
+	var err error
+	cc := si.metrics.consumedCapacity()
+	defer si.recordMetrics(ctx, OpRead, key, &err, cc)()
+
+	if err = isValidKey(key); err != nil {
+		return false, err
+	}
+	consistent, err := si.isConsistentRead(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	err = projectQuery(buildTableKeyCondition(si.table(key.TableName()), key), key).Index(si.name).
+		Consistent(consistent).
+		ConsumedCapacity(cc).
+		AllWithContext(ctx, items)
+	if err != nil {
+		if errors.Is(err, dynamo.ErrNotFound) {
+			si.log.WithContext(ctx).WithField(TableName, key.TableName()).Info(ErrNoItemFound.Error())
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	val := reflect.ValueOf(items)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	if val.Kind() == reflect.Array || val.Kind() == reflect.Slice {
+		if val.Len() == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (si secondaryIndex) table(tableName string) dynamo.Table {
+	return si.dynamoClient.Table(tableName)
+}
+
+// isConsistentRead returns true if the read of key is strongly consistent, returns ErrConsistentReadNotSupported
+// if a strongly consistent read was requested from an index that does not support them
+func (si secondaryIndex) isConsistentRead(ctx context.Context, key KeyInterface) (bool, error) {
+	if !si.consistentReads {
+		return false, validateGlobalIndexRead(ctx, key)
+	}
+	return isConsistentRead(ctx, key, si.consistentReadTables), nil
+}
+
+// QueryWithContext by query; it accepts a query interface that is used to get the table name, hash key and range key with its operator if it exists;
+// context which used to enable log with context, the output will be given in items
+// returns error in case of error
+func (si secondaryIndex) QueryWithContext(ctx context.Context, query QueryInterface, item any) (err error) {
+	cc := si.metrics.consumedCapacity()
+	defer si.recordMetrics(ctx, OpRead, query, &err, cc)()
+
+	if !IsPointerOFSlice(item) {
+		return ErrInvalidPointerSliceType
+	}
+	if err = isValidKey(query); err != nil {
+		return err
+	}
+	consistent, err := si.isConsistentRead(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	q, err := buildKeyQuery(si.table(query.TableName()), si.name, query, si.pageTokenCodec)
+	if err != nil {
+		return err
+	}
+	q = projectQuery(q, query).Consistent(consistent).ConsumedCapacity(cc)
+
+	q = limitQuery(q, query)
+
+	err = q.AllWithContext(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// QueryPageWithContext queries a single page; it accepts a query interface like QueryWithContext, the search limit of the query, or else its limit, is the page size
+// and a page token set on the query resumes after the page it was returned for; the output will be given in items.
+// With a filter expression a page may hold fewer items than the limit
+// returns the token of the next page, empty if there are no more pages, returns an empty token and an error in case of error
+func (si secondaryIndex) QueryPageWithContext(ctx context.Context, query QueryInterface, items any) (string, error) {
+	var err error
+	cc := si.metrics.consumedCapacity()
+	defer si.recordMetrics(ctx, OpRead, query, &err, cc)()
+
+	if !IsPointerOFSlice(items) {
+		err = ErrInvalidPointerSliceType
+		return "", err
+	}
+	if err = isValidKey(query); err != nil {
+		return "", err
+	}
+	consistent, err := si.isConsistentRead(ctx, query)
+	if err != nil {
+		return "", err
+	}
+
+	q, err := buildKeyQuery(si.table(query.TableName()), si.name, query, si.pageTokenCodec)
+	if err != nil {
+		return "", err
+	}
+	q = projectQuery(q, query).Consistent(consistent).ConsumedCapacity(cc)
+
+	// the search limit stops the query after a single request, so the last evaluated key is the end of the page
+	if limit := pageSize(query); limit > 0 {
+		q = q.SearchLimit(limit)
+	}
+
+	lastEvaluatedKey, err := q.AllWithLastEvaluatedKeyContext(ctx, items)
+	if err != nil {
+		return "", err
+	}
+
+	nextPageToken, err := encodePageToken(si.pageTokenCodec, query, si.name, lastEvaluatedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return nextPageToken, nil
+}
+
+// QueryIteratorWithContext returns an iterator for the items of a query; it accepts a query interface like QueryWithContext,
+// pages are fetched while iterating, so items are not loaded into memory at once
+// returns an error if the query is invalid, errors while iterating are returned by Err of the iterator
+func (si secondaryIndex) QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error) {
+	var err error
+	cc := si.metrics.consumedCapacity()
+	itrMetrics := newIterationMetrics(withIndexLabel(ctx, si.name), si.metrics, OpRead, query, cc)
+	defer itrMetrics.creationFailed(&err)
+
+	if err = isValidKey(query); err != nil {
+		return nil, err
+	}
+	consistent, err := si.isConsistentRead(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := buildKeyQuery(si.table(query.TableName()), si.name, query, si.pageTokenCodec)
+	if err != nil {
+		return nil, err
+	}
+	q = projectQuery(q, query).Consistent(consistent).ConsumedCapacity(cc)
+
+	q = limitQuery(q, query)
+
+	return &QueryIterator{
+		iterator: q.Iter(),
+		ctx:      ctx,
+		metrics:  itrMetrics,
+	}, nil
+}
+
+// CountWithContext counts the items of a query without reading them; it accepts a query interface like QueryWithContext,
+// the filter expression of the query restricts the counted items. All pages are counted, the limits and the projection of the query are ignored
+// returns the number of items, returns 0 and an error in case of error
+func (si secondaryIndex) CountWithContext(ctx context.Context, query QueryInterface) (int64, error) {
+	var err error
+	cc := si.metrics.consumedCapacity()
+	defer si.recordMetrics(ctx, OpRead, query, &err, cc)()
+
+	if err = isValidKey(query); err != nil {
+		return 0, err
+	}
+	consistent, err := si.isConsistentRead(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	q, err := buildKeyQuery(si.table(query.TableName()), si.name, query, si.pageTokenCodec)
+	if err != nil {
+		return 0, err
+	}
+	q = q.Consistent(consistent).ConsumedCapacity(cc)
+
+	count, err := q.CountWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MultiQueryWithContext runs the queries of a multi query concurrently and merges their items in the order of the range key,
+// ascending or descending like the queries; the items of all queries must hold the range key. The limit of the multi query
+// caps the merged items and the items read by every query; the output will be given in items
+// returns a MultiQueryError if some queries failed, items then holds the merged items of the other queries, returns an error in case of error
+func (si secondaryIndex) MultiQueryWithContext(ctx context.Context, multiQuery MultiQueryInterface, items any) error {
+	return runMultiQuery(ctx, multiQuery, items, si.QueryWithContext)
+}
+
+func (si secondaryIndex) recordMetrics(ctx context.Context, op string, key KeyInterface, err *error, cc *dynamo.ConsumedCapacity) func() {
+	start := time.Now()
+	return func() {
+		ctx := withIndexLabel(ctx, si.name)
+		si.metrics.Record(ctx, op, key, time.Since(start), isOpSuccess(err))
+		si.metrics.recordConsumedCapacity(ctx, op, key, cc)
+	}
+}
@@ -22,3 +22,45 @@ var ErrInvalidPointerSliceType = errors.New("invalid type expected pointer of sl
 
 // ErrInvalidBatchRequest batch request should be for same table
 var ErrInvalidBatchRequest = errors.New("batch request with multiple tables")
+
+// ErrInvalidTransactOperation transaction item has an unknown operation
+var ErrInvalidTransactOperation = errors.New("invalid transaction operation")
+
+// ErrMissingConditionExpression condition check requires a condition expression
+var ErrMissingConditionExpression = errors.New("missing condition expression")
+
+// ErrInvalidPageToken page token is malformed error
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrInvalidPageTokenKey page token signing or encryption key is invalid error
+var ErrInvalidPageTokenKey = errors.New("invalid page token key")
+
+// ErrInvalidRangeCondition range key values do not match the operator of the query
+var ErrInvalidRangeCondition = errors.New("invalid range key condition")
+
+// ErrConsistentReadNotSupported global secondary indexes do not support strongly consistent reads error
+var ErrConsistentReadNotSupported = errors.New("consistent read is not supported on global secondary indexes")
+
+// ErrInvalidExpressionArgs args of an expression do not match its placeholders
+var ErrInvalidExpressionArgs = errors.New("expression args do not match placeholders")
+
+// ErrInvalidMultiQuery queries of a multi query have different orders or range key names, or no range key name to merge by
+var ErrInvalidMultiQuery = errors.New("queries of multi query have different orders or range key names")
+
+// ErrMissingTableKey item of an index does not hold a key attribute of the table
+var ErrMissingTableKey = errors.New("index item misses a key attribute of the table")
+
+// ErrInvalidTotalSegments parallel scan needs at least one segment
+var ErrInvalidTotalSegments = errors.New("invalid total segments of parallel scan")
+
+// ErrInvalidScanCheckpoint checkpoint was taken for a different scan
+var ErrInvalidScanCheckpoint = errors.New("scan checkpoint does not match the scan")
+
+// ErrUnprocessedBatchRequest dynamodb left keys or items of a batch request unprocessed after all retries
+var ErrUnprocessedBatchRequest = errors.New("unprocessed batch request")
+
+// ErrInvalidBatchProjection keys of a batch get have different projections
+var ErrInvalidBatchProjection = errors.New("keys of batch get have different projections")
+
+// ErrInvalidCondition condition cannot be rendered, e.g. In without values
+var ErrInvalidCondition = errors.New("invalid condition")
@@ -1,9 +1,29 @@
 package djoemo
 
 import (
+	"encoding"
+	"encoding/base32"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/guregu/dynamo"
 )
 
+const (
+	// maxBatchGetKeys is the maximum number of keys dynamodb accepts in one batch get request
+	maxBatchGetKeys    = 100
+	minBatchGetBackoff = 50 * time.Millisecond
+	maxBatchGetBackoff = 5 * time.Second
+	// maxBatchWriteItems is the maximum number of items dynamodb accepts in one batch write request
+	maxBatchWriteItems = 25
+	// maxUnprocessedRetries is the number of times unprocessed keys or items of a batch request are retried
+	maxUnprocessedRetries = 5
+)
+
 func valueFromPtr[T any](ptr *T) T {
 	if ptr == nil {
 		var v T
@@ -28,3 +48,256 @@ func buildTableKeyCondition(table dynamo.Table, key KeyInterface) *dynamo.Query
 
 	return q
 }
+
+// limitQuery applies the result limit and the evaluation limit of query
+func limitQuery(q *dynamo.Query, query QueryInterface) *dynamo.Query {
+	if limit := valueFromPtr(query.Limit()); limit > 0 {
+		q = q.Limit(limit)
+	}
+	if searchLimit := valueFromPtr(querySearchLimit(query)); searchLimit > 0 {
+		q = q.SearchLimit(searchLimit)
+	}
+
+	return q
+}
+
+// pageSize returns the number of items a page of query evaluates, the evaluation limit if it is set, otherwise the result limit
+func pageSize(query QueryInterface) int64 {
+	if searchLimit := valueFromPtr(querySearchLimit(query)); searchLimit > 0 {
+		return searchLimit
+	}
+
+	return valueFromPtr(query.Limit())
+}
+
+// projectQuery restricts the query to the projection of the key if it has one
+func projectQuery(q *dynamo.Query, key KeyInterface) *dynamo.Query {
+	if projection := keyProjection(key); len(projection) > 0 {
+		q = q.Project(projection...)
+	}
+
+	return q
+}
+
+// projectionExpression builds a projection expression for the given attribute paths; every attribute name is
+// replaced by a placeholder so reserved words can be projected, list indexes like Items[0] are kept as they are
+func projectionExpression(attributes []string) (string, map[string]*string) {
+	names := make(map[string]*string)
+	paths := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		elements := strings.Split(attribute, ".")
+		for j, element := range elements {
+			name, index, _ := strings.Cut(element, "[")
+			placeholder := "#p" + strconv.Itoa(len(names))
+			names[placeholder] = &name
+			elements[j] = placeholder
+			if index != "" {
+				elements[j] += "[" + index
+			}
+		}
+		paths[i] = strings.Join(elements, ".")
+	}
+
+	return strings.Join(paths, ", "), names
+}
+
+// dynamoKey marshals the hash key and, if withRange is set, the range key of key
+func dynamoKey(key KeyInterface, withRange bool) (map[string]*dynamodb.AttributeValue, error) {
+	hashKey, err := dynamo.Marshal(key.HashKey())
+	if err != nil {
+		return nil, err
+	}
+	dKey := map[string]*dynamodb.AttributeValue{*key.HashKeyName(): hashKey}
+
+	if withRange {
+		rangeKey, err := dynamo.Marshal(key.RangeKey())
+		if err != nil {
+			return nil, err
+		}
+		dKey[*key.RangeKeyName()] = rangeKey
+	}
+
+	return dKey, nil
+}
+
+// itemKeyID identifies an item by the values of its key attributes keyNames; key attributes are strings, numbers or binaries
+func itemKeyID(item map[string]*dynamodb.AttributeValue, keyNames []string) string {
+	var id strings.Builder
+	for _, name := range keyNames {
+		av := item[name]
+		switch {
+		case av == nil:
+		case av.S != nil:
+			id.WriteString("S" + *av.S)
+		case av.N != nil:
+			id.WriteString("N" + *av.N)
+		default:
+			id.WriteString("B" + string(av.B))
+		}
+		id.WriteByte(0)
+	}
+
+	return id.String()
+}
+
+// buildKeyQuery builds the query on the table or, if indexName is set, on the index for the key conditions, order, filter
+// and page token of query; the projection and the limit are left to the caller, dynamodb rejects projections when only counting items
+func buildKeyQuery(table dynamo.Table, indexName string, query QueryInterface, codec PageTokenCodecInterface) (*dynamo.Query, error) {
+	q := table.Get(*query.HashKeyName(), query.HashKey())
+
+	if indexName != "" {
+		q = q.Index(indexName)
+	}
+
+	// by range
+	if rangeValues := queryRangeValues(query); query.RangeKeyName() != nil && len(rangeValues) > 0 {
+		if err := validateRangeCondition(query); err != nil {
+			return nil, err
+		}
+		q = q.Range(*query.RangeKeyName(), dynamo.Operator(query.RangeOp()), rangeValues...)
+	}
+
+	if query.Descending() {
+		q = q.Order(dynamo.Descending)
+	}
+
+	if filter, args := queryFilter(query); filter != "" {
+		q = q.Filter(filter, args...)
+	}
+
+	if pageToken := queryPageToken(query); pageToken != "" {
+		startKey, err := codec.Decode(newPageTokenScope(query, indexName), pageToken)
+		if err != nil {
+			return nil, err
+		}
+		q = q.StartFrom(startKey)
+	}
+
+	return q, nil
+}
+
+// encodePageToken encodes the last evaluated key of a page of query into a page token
+// returns an empty token if there is no next page
+func encodePageToken(codec PageTokenCodecInterface, query QueryInterface, indexName string, lastEvaluatedKey dynamo.PagingKey) (string, error) {
+	if len(lastEvaluatedKey) == 0 {
+		return "", nil
+	}
+
+	return codec.Encode(newPageTokenScope(query, indexName), lastEvaluatedKey)
+}
+
+// expression is a condition or filter expression with its placeholders, ready to be sent with the dynamodb client
+type expression struct {
+	expression string
+	names      map[string]*string
+	values     map[string]*dynamodb.AttributeValue
+}
+
+// buildExpression substitutes the placeholders of an expression in the syntax of guregu/dynamo for requests that are sent with the
+// dynamodb client directly: every ? is replaced by a value and every $ by an attribute name from args, in order, and names
+// in single quotes are replaced by placeholders as well. The placeholders and the parentheses match the ones guregu/dynamo uses for filters
+// returns ErrInvalidExpressionArgs if the args do not match the placeholders or a quoted name is not closed
+func buildExpression(expr string, args []any) (expression, error) {
+	var built expression
+	var substituted strings.Builder
+	next := 0
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '\'':
+			end := strings.IndexByte(expr[i+1:], '\'')
+			if end == -1 {
+				return expression{}, ErrInvalidExpressionArgs
+			}
+			substituted.WriteString(built.name(expr[i+1 : i+1+end]))
+			i += end + 1
+		case '$', '?':
+			if next == len(args) {
+				return expression{}, ErrInvalidExpressionArgs
+			}
+			var placeholder string
+			var err error
+			if expr[i] == '$' {
+				placeholder, err = built.nameArg(args[next])
+			} else {
+				placeholder, err = built.value(args[next])
+			}
+			if err != nil {
+				return expression{}, err
+			}
+			substituted.WriteString(placeholder)
+			next++
+		default:
+			substituted.WriteByte(expr[i])
+		}
+	}
+	if next != len(args) {
+		return expression{}, ErrInvalidExpressionArgs
+	}
+
+	built.expression = substituted.String()
+	if trimmed := strings.TrimSpace(built.expression); trimmed != "" && (trimmed[0] != '(' || trimmed[len(trimmed)-1] != ')') {
+		built.expression = "(" + built.expression + ")"
+	}
+	return built, nil
+}
+
+// name adds the placeholder of an attribute name, it is derived from the name, so the same name always has the same placeholder
+func (e *expression) name(name string) string {
+	if e.names == nil {
+		e.names = make(map[string]*string)
+	}
+
+	placeholder := "#s" + strings.TrimRight(base32.StdEncoding.EncodeToString([]byte(name)), "=")
+	e.names[placeholder] = aws.String(name)
+	return placeholder
+}
+
+// nameArg adds the placeholder of the attribute name arg of a $; ints are inserted as they are, e.g. for list indexes
+func (e *expression) nameArg(arg any) (string, error) {
+	switch name := arg.(type) {
+	case encoding.TextMarshaler:
+		text, err := name.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return e.name(string(text)), nil
+	case string:
+		return e.name(name), nil
+	case int:
+		return strconv.Itoa(name), nil
+	case int64:
+		return strconv.FormatInt(name, 10), nil
+	}
+	return "", fmt.Errorf("type of argument for $ must be string, int, or int64 (got %T)", arg)
+}
+
+// value adds the placeholder of the value arg of a ?
+func (e *expression) value(arg any) (string, error) {
+	av, err := dynamo.Marshal(arg)
+	if err != nil {
+		return "", err
+	}
+	if e.values == nil {
+		e.values = make(map[string]*dynamodb.AttributeValue)
+	}
+
+	placeholder := ":v" + strconv.Itoa(len(e.values))
+	e.values[placeholder] = av
+	return placeholder, nil
+}
+
+// expressionNames returns the names of the expression, nil if it has none, dynamodb rejects empty maps
+func (e expression) expressionNames() map[string]*string {
+	if len(e.names) == 0 {
+		return nil
+	}
+	return e.names
+}
+
+// expressionValues returns the values of the expression, nil if it has none, dynamodb rejects empty maps
+func (e expression) expressionValues() map[string]*dynamodb.AttributeValue {
+	if len(e.values) == 0 {
+		return nil
+	}
+	return e.values
+}
@@ -4,32 +4,214 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/guregu/dynamo"
 )
 
-// IteratorInterface ...
+//go:generate mockgen -source=iterator.go -destination=./mock/iterator.go -package=mock .
+
+// IteratorInterface provides an interface for iterating items; NextItem returns false both at the end of the items
+// and if an error stopped the iteration, so Err must be checked once it returns false
 type IteratorInterface interface {
+	// NextItem unmarshals the next item into out and returns false if there are no more items or an error occurred
 	NextItem(out interface{}) bool
+	// Err returns the error that stopped the iteration, nil if all items were iterated; it is the error of the context
+	// if the context was cancelled or its deadline exceeded
+	Err() error
+}
+
+// ScanIteratorInterface provides an interface for iterating the items of a scan that can be resumed
+type ScanIteratorInterface interface {
+	IteratorInterface
+	// Checkpoint returns the position of the scan; a scan started from it with WithScanCheckpoint continues with the items
+	// after the ones returned so far, the items of the current page may be returned again
+	Checkpoint() ScanCheckpoint
 }
 
-// Iterator ...
+// Iterator iterates the items of a scan and fetches the pages lazily
 type Iterator struct {
-	scan             *dynamo.Scan
-	tableName        string
-	searchLimit      int64
-	lastEvaluatedKey map[string]*dynamodb.AttributeValue
-	iterator         dynamo.PagingIter
-	dynamoClient     *dynamo.DB
-	ctx              context.Context
+	pager *scanPager
+	// items are the items of the current page that were not returned yet
+	items []map[string]*dynamodb.AttributeValue
+	ctx   context.Context
+	// limit is the result limit, 0 if the items are not limited
+	limit int64
+	// count is the number of items returned
+	count int64
+	// done is set once the iteration ended, err is the error that stopped it
+	done bool
+	err  error
+	// cc accumulates the capacity consumed by the pages, it is nil if the capacity is not recorded
+	cc *dynamo.ConsumedCapacity
+	// checkpointStore stores the checkpoint after every page under checkpointName, it is nil if the scan is not resumable
+	checkpointStore CheckpointStoreInterface
+	checkpointName  string
+	// metrics records the capacity consumed by every page and whether the iteration failed, it is nil if nothing is recorded
+	metrics *iterationMetrics
 }
 
-// NextItem unmarshals the next item into out and returns if there are more items following
+// NextItem unmarshals the next item into out and returns false if there are no more items or an error occurred
 func (itr *Iterator) NextItem(out interface{}) bool {
-	more := itr.iterator.NextWithContext(itr.ctx, out)
-	if !more && itr.iterator.LastEvaluatedKey() != nil {
-		itr.scan = itr.scan.StartFrom(itr.iterator.LastEvaluatedKey())
-		itr.iterator = itr.scan.Iter()
-		return itr.iterator.NextWithContext(itr.ctx, out)
+	if itr.done {
+		return false
+	}
+	if itr.limit > 0 && itr.count >= itr.limit {
+		return itr.stop(nil)
+	}
+	if err := itr.ctx.Err(); err != nil {
+		return itr.stop(err)
+	}
+
+	for len(itr.items) == 0 {
+		if itr.pager.done {
+			return itr.stop(itr.saveCheckpoint())
+		}
+		if err := itr.nextPage(); err != nil {
+			return itr.stop(iterationError(itr.ctx, err))
+		}
 	}
-	return more
+
+	item := itr.items[0]
+	itr.items = itr.items[1:]
+	if err := dynamo.UnmarshalItem(item, out); err != nil {
+		return itr.stop(err)
+	}
+	itr.count++
+	return true
+}
+
+// Err returns the error that stopped the iteration, nil if all items were iterated; it is the error of the context
+// if the context was cancelled or its deadline exceeded
+func (itr *Iterator) Err() error {
+	return itr.err
+}
+
+// stop ends the iteration with err and returns false
+func (itr *Iterator) stop(err error) bool {
+	itr.done = true
+	itr.err = err
+	itr.metrics.iterationEnded(err)
+	itr.metrics = nil
+	return false
+}
+
+// Checkpoint returns the position of the scan; a scan started from it with WithScanCheckpoint continues with the items
+// after the ones returned so far, the items of the current page may be returned again
+func (itr *Iterator) Checkpoint() ScanCheckpoint {
+	return itr.pager.checkpoint(len(itr.items) == 0)
+}
+
+// nextPage stores the checkpoint of the processed pages and reads the next page
+func (itr *Iterator) nextPage() error {
+	if err := itr.saveCheckpoint(); err != nil {
+		return err
+	}
+
+	output, err := itr.pager.next(itr.ctx)
+	if err != nil {
+		return err
+	}
+	addConsumedCapacity(itr.cc, output.ConsumedCapacity)
+	itr.metrics.pageRead()
+	itr.items = output.Items
+
+	return nil
+}
+
+// saveCheckpoint stores the checkpoint if the scan is resumable
+func (itr *Iterator) saveCheckpoint() error {
+	if itr.checkpointStore == nil {
+		return nil
+	}
+
+	return itr.checkpointStore.Save(itr.ctx, itr.checkpointName, itr.Checkpoint())
+}
+
+// QueryIteratorInterface provides an interface for iterating the items of a query
+type QueryIteratorInterface interface {
+	IteratorInterface
+}
+
+// QueryIterator iterates the items of a query and fetches the pages lazily
+type QueryIterator struct {
+	iterator dynamo.PagingIter
+	ctx      context.Context
+	// done is set once the iteration ended, err is the error that stopped it
+	done bool
+	err  error
+	// metrics records the capacity consumed by every page and whether the iteration failed, it is nil if nothing is recorded
+	metrics *iterationMetrics
+}
+
+// NextItem unmarshals the next item into out and returns false if there are no more items or an error occurred
+func (itr *QueryIterator) NextItem(out interface{}) bool {
+	if itr.done {
+		return false
+	}
+	if err := itr.ctx.Err(); err != nil {
+		return itr.stop(err)
+	}
+
+	// guregu/dynamo reads the next page within NextWithContext and adds its capacity
+	next := itr.iterator.NextWithContext(itr.ctx, out)
+	itr.metrics.pageRead()
+	if !next {
+		return itr.stop(iterationError(itr.ctx, itr.iterator.Err()))
+	}
+	return true
+}
+
+// Err returns the error that stopped the iteration, nil if all items were iterated; it is the error of the context
+// if the context was cancelled or its deadline exceeded
+func (itr *QueryIterator) Err() error {
+	return itr.err
+}
+
+// stop ends the iteration with err and returns false
+func (itr *QueryIterator) stop(err error) bool {
+	itr.done = true
+	itr.err = err
+	itr.metrics.iterationEnded(err)
+	itr.metrics = nil
+	return false
+}
+
+// iterationError returns the error of the context if it is done, the aws client wraps it into an error of its own
+// that does not unwrap to it; otherwise err is returned
+func iterationError(ctx context.Context, err error) error {
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+	}
+	return err
+}
+
+// newScanIterator creates the iterator of the scan of the table of key, or of the index if indexName is set; the scan
+// starts from the checkpoint of options and, if it is the checkpoint of a segment, scans only that segment
+func newScanIterator(ctx context.Context, client dynamodbiface.DynamoDBAPI, key KeyInterface, indexName string, searchLimit int64,
+	consistent bool, options scanOptions, cc *dynamo.ConsumedCapacity,
+) (*Iterator, error) {
+	input, err := newScanInput(key, indexName, searchLimit, consistent, options, cc)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := options.iteratorCheckpoint(ctx, key.TableName(), indexName)
+	if err != nil {
+		return nil, err
+	}
+
+	var segment, totalSegments int
+	if checkpoint != nil {
+		segment, totalSegments = checkpoint.Segment, checkpoint.TotalSegments
+	}
+
+	return &Iterator{
+		pager:           newScanPager(client, input, segment, totalSegments, checkpoint, options.reads(ctx)),
+		ctx:             ctx,
+		limit:           options.limit,
+		cc:              cc,
+		checkpointStore: options.checkpointStore,
+		checkpointName:  options.checkpointName,
+	}, nil
 }
@@ -1,11 +1,13 @@
 package djoemo
 
 type key struct {
-	tableName    string
-	hashKeyName  *string
-	rangeKeyName *string
-	hashKey      interface{}
-	rangeKey     interface{}
+	tableName      string
+	hashKeyName    *string
+	rangeKeyName   *string
+	hashKey        interface{}
+	rangeKey       interface{}
+	projection     []string
+	consistentRead bool
 }
 
 // Key factory method to create struct that implements key interface
@@ -43,6 +45,18 @@ func (k *key) WithRangeKey(rangeKey interface{}) *key {
 	return k
 }
 
+// WithProjection set djoemo key projection; only the given attributes are read and unmarshalled
+func (k *key) WithProjection(attributes ...string) *key {
+	k.projection = attributes
+	return k
+}
+
+// WithConsistentRead set djoemo key to be read strongly consistent
+func (k *key) WithConsistentRead() *key {
+	k.consistentRead = true
+	return k
+}
+
 // TableName returns the djoemo table name
 func (k *key) TableName() string {
 	return k.tableName
@@ -68,6 +82,16 @@ func (k *key) RangeKey() interface{} {
 	return k.rangeKey
 }
 
+// Projection returns the attributes to read, empty if all attributes are read
+func (k *key) Projection() []string {
+	return k.projection
+}
+
+// ConsistentRead returns true if the key is read strongly consistent
+func (k *key) ConsistentRead() bool {
+	return k.consistentRead
+}
+
 func isValidKey(key KeyInterface) error {
 	if err := isValidTableName(key); err != nil {
 		return err
@@ -13,3 +13,31 @@ type KeyInterface interface {
 	// HashKey returns the range key value
 	RangeKey() any
 }
+
+// ProjectionKeyInterface is implemented by keys that read only some attributes of an item
+type ProjectionKeyInterface interface {
+	// Projection returns the attributes to read, empty if all attributes are read
+	Projection() []string
+}
+
+// ConsistentReadKeyInterface is implemented by keys that can be read strongly consistent
+type ConsistentReadKeyInterface interface {
+	// ConsistentRead returns true if the key is read strongly consistent
+	ConsistentRead() bool
+}
+
+// keyProjection returns the projection of key, empty if key does not implement ProjectionKeyInterface
+func keyProjection(key KeyInterface) []string {
+	if projectionKey, ok := key.(ProjectionKeyInterface); ok {
+		return projectionKey.Projection()
+	}
+	return nil
+}
+
+// keyConsistentRead returns true if key implements ConsistentReadKeyInterface and is read strongly consistent
+func keyConsistentRead(key KeyInterface) bool {
+	if consistentKey, ok := key.(ConsistentReadKeyInterface); ok {
+		return consistentKey.ConsistentRead()
+	}
+	return false
+}
@@ -2,8 +2,15 @@ package djoemo
 
 import (
 	"context"
+	"maps"
 	"sync"
 	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/guregu/dynamo"
 )
 
 // MetricsInterface provides an interface for metrics publisher
@@ -13,8 +20,32 @@ type MetricsInterface interface {
 	Record(ctx context.Context, caller string, key KeyInterface, duration time.Duration, success bool)
 }
 
+// CapacityMetricsInterface is an optional interface for metrics publishers that record the capacity consumed by operations;
+// consumed capacity is only requested from dynamodb if a publisher of the repository implements it
+type CapacityMetricsInterface interface {
+	RecordConsumedCapacity(ctx context.Context, caller string, key KeyInterface, capacity ConsumedCapacity)
+}
+
+// ConsumedCapacity is the throughput capacity consumed by an operation; reads consume read capacity units
+// and all other operations write capacity units
+type ConsumedCapacity struct {
+	// TableName is the name of the table reported by dynamodb
+	TableName string
+	// Total is the number of capacity units consumed by the operation
+	Total float64
+	// Read is the number of read capacity units consumed; only reported for transactions
+	Read float64
+	// Write is the number of write capacity units consumed; only reported for transactions
+	Write float64
+	// Table is the number of capacity units consumed on the table
+	Table float64
+	// Indexes maps the names of the global and local secondary indexes to the capacity units consumed on them
+	Indexes map[string]float64
+}
+
 const (
 	labelSource = "source"
+	labelIndex  = "index"
 
 	StatusSuccess = "success"
 	StatusFailure = "failure"
@@ -23,6 +54,8 @@ const (
 	OpUpdate = "update"
 	OpRead   = "read"
 	OpDelete = "delete"
+
+	OpTransaction = "transaction"
 )
 
 type customMetricsLabelsContextKey int
@@ -55,6 +88,24 @@ func WithSourceLabel(ctx context.Context, value string) context.Context {
 	return AddMetrics(ctx, labelSource, value)
 }
 
+// withIndexLabel returns a context labelling metrics with the index name; the labels of ctx are copied,
+// so the label does not leak into operations of the caller that use ctx
+func withIndexLabel(ctx context.Context, indexName string) context.Context {
+	labels := &customLabels{
+		Labels: map[string]string{labelIndex: indexName},
+	}
+	if parent, ok := ctx.Value(customLabelsCtxKey).(*customLabels); ok && parent != nil {
+		parent.RLock()
+		for key, value := range parent.Labels {
+			labels.Labels[key] = value
+		}
+		parent.RUnlock()
+		labels.Labels[labelIndex] = indexName
+	}
+
+	return context.WithValue(ctx, customLabelsCtxKey, labels)
+}
+
 func GetLabelsFromContext(ctx context.Context) map[string]string {
 	customLabels, ok := ctx.Value(customLabelsCtxKey).(*customLabels)
 	if !ok || customLabels == nil {
@@ -86,3 +137,187 @@ func (m *Metrics) RecordMultiple(ctx context.Context, op string, key []KeyInterf
 		m.Record(ctx, op, key, duration, success)
 	}
 }
+
+// RecordConsumedCapacity records the consumed capacity with all metrics publishers that implement CapacityMetricsInterface
+func (m *Metrics) RecordConsumedCapacity(ctx context.Context, op string, key KeyInterface, capacity ConsumedCapacity) {
+	for _, metric := range m.metrics {
+		if capacityMetric, ok := metric.(CapacityMetricsInterface); ok {
+			capacityMetric.RecordConsumedCapacity(ctx, op, key, capacity)
+		}
+	}
+}
+
+// recordConsumedCapacity records the capacity an operation added to cc; nothing is recorded if cc is nil or no capacity was consumed
+func (m *Metrics) recordConsumedCapacity(ctx context.Context, op string, key KeyInterface, cc *dynamo.ConsumedCapacity) {
+	if cc == nil || (cc.Total == 0 && cc.Table == 0 && len(cc.GSI) == 0 && len(cc.LSI) == 0) {
+		return
+	}
+
+	capacity := ConsumedCapacity{
+		TableName: cc.TableName,
+		Total:     cc.Total,
+		Read:      cc.Read,
+		Write:     cc.Write,
+		Table:     cc.Table,
+	}
+	if len(cc.GSI) > 0 || len(cc.LSI) > 0 {
+		capacity.Indexes = make(map[string]float64, len(cc.GSI)+len(cc.LSI))
+		maps.Copy(capacity.Indexes, cc.GSI)
+		maps.Copy(capacity.Indexes, cc.LSI)
+	}
+
+	m.RecordConsumedCapacity(ctx, op, key, capacity)
+}
+
+// iterationMetrics records the metrics of an iteration: the capacity consumed by every page and the operation once when
+// the iteration ends, as failure if it ends in an error or the iterator cannot be created
+type iterationMetrics struct {
+	ctx     context.Context
+	metrics *Metrics
+	op      string
+	key     KeyInterface
+	// cc accumulates the capacity of the pages read since the last page was recorded, it is nil if the capacity is not recorded
+	cc    *dynamo.ConsumedCapacity
+	start time.Time
+}
+
+// newIterationMetrics returns the metrics of an iteration that adds the capacity of its pages to cc
+func newIterationMetrics(ctx context.Context, metrics *Metrics, op string, key KeyInterface, cc *dynamo.ConsumedCapacity) *iterationMetrics {
+	return &iterationMetrics{ctx: ctx, metrics: metrics, op: op, key: key, cc: cc, start: time.Now()}
+}
+
+// pageRead records the capacity added to cc since the last call and resets cc; the capacity is recorded as soon as a page
+// is read, so the capacity of an iteration that is abandoned before it ends is recorded as well
+func (m *iterationMetrics) pageRead() {
+	if m == nil || m.cc == nil {
+		return
+	}
+	m.metrics.recordConsumedCapacity(m.ctx, m.op, m.key, m.cc)
+	*m.cc = dynamo.ConsumedCapacity{}
+}
+
+// iterationEnded records the capacity not recorded yet and the operation, as failure if err is not nil
+func (m *iterationMetrics) iterationEnded(err error) {
+	if m == nil {
+		return
+	}
+	m.pageRead()
+	m.metrics.Record(m.ctx, m.op, m.key, time.Since(m.start), err == nil)
+}
+
+// creationFailed ends the iteration with err if the iterator could not be created; it is deferred by the iterator constructors
+func (m *iterationMetrics) creationFailed(err *error) {
+	if *err != nil {
+		m.iterationEnded(*err)
+	}
+}
+
+// consumedCapacity returns the consumed capacity an operation adds to; it returns nil if no metrics publisher
+// records consumed capacity, so it is not requested from dynamodb
+func (m *Metrics) consumedCapacity() *dynamo.ConsumedCapacity {
+	for _, metric := range m.metrics {
+		if _, ok := metric.(CapacityMetricsInterface); ok {
+			return &dynamo.ConsumedCapacity{}
+		}
+	}
+
+	return nil
+}
+
+// returnConsumedCapacity returns the ReturnConsumedCapacity value of requests sent by the client directly, nil if cc is nil
+func returnConsumedCapacity(cc *dynamo.ConsumedCapacity) *string {
+	if cc == nil {
+		return nil
+	}
+
+	return aws.String(dynamodb.ReturnConsumedCapacityIndexes)
+}
+
+// addConsumedCapacity adds the capacity of a response of the client to cc, like guregu/dynamo does for its operations
+func addConsumedCapacity(cc *dynamo.ConsumedCapacity, consumed *dynamodb.ConsumedCapacity) {
+	if cc == nil || consumed == nil {
+		return
+	}
+
+	cc.Total += aws.Float64Value(consumed.CapacityUnits)
+	cc.Read += aws.Float64Value(consumed.ReadCapacityUnits)
+	cc.Write += aws.Float64Value(consumed.WriteCapacityUnits)
+	if consumed.Table != nil {
+		cc.Table += aws.Float64Value(consumed.Table.CapacityUnits)
+	}
+	for name, index := range consumed.GlobalSecondaryIndexes {
+		if cc.GSI == nil {
+			cc.GSI = make(map[string]float64)
+		}
+		cc.GSI[name] += aws.Float64Value(index.CapacityUnits)
+	}
+	for name, index := range consumed.LocalSecondaryIndexes {
+		if cc.LSI == nil {
+			cc.LSI = make(map[string]float64)
+		}
+		cc.LSI[name] += aws.Float64Value(index.CapacityUnits)
+	}
+	if consumed.TableName != nil {
+		cc.TableName = *consumed.TableName
+	}
+}
+
+// tableCapacities collects the capacity consumed by a transaction per table; guregu/dynamo adds the capacities dynamodb
+// reports for the tables of a transaction into one, so the transaction is sent through it to keep them apart
+type tableCapacities struct {
+	dynamodbiface.DynamoDBAPI
+	capacities map[string]*dynamo.ConsumedCapacity
+}
+
+// newTableCapacities returns the capacities of a transaction sent by client, nil if cc is nil since the capacity is not recorded
+func newTableCapacities(client dynamodbiface.DynamoDBAPI, cc *dynamo.ConsumedCapacity) *tableCapacities {
+	if cc == nil {
+		return nil
+	}
+
+	return &tableCapacities{DynamoDBAPI: client, capacities: make(map[string]*dynamo.ConsumedCapacity)}
+}
+
+// TransactWriteItemsWithContext sends the transaction and adds the capacity of every table
+func (c *tableCapacities) TransactWriteItemsWithContext(ctx aws.Context, input *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	output, err := c.DynamoDBAPI.TransactWriteItemsWithContext(ctx, input, opts...)
+	if output != nil {
+		c.add(output.ConsumedCapacity...)
+	}
+	return output, err
+}
+
+// TransactGetItemsWithContext sends the transaction and adds the capacity of every table
+func (c *tableCapacities) TransactGetItemsWithContext(ctx aws.Context, input *dynamodb.TransactGetItemsInput, opts ...request.Option) (*dynamodb.TransactGetItemsOutput, error) {
+	output, err := c.DynamoDBAPI.TransactGetItemsWithContext(ctx, input, opts...)
+	if output != nil {
+		c.add(output.ConsumedCapacity...)
+	}
+	return output, err
+}
+
+// add adds the consumed capacities to the capacity of their tables
+func (c *tableCapacities) add(consumed ...*dynamodb.ConsumedCapacity) {
+	if c == nil {
+		return
+	}
+
+	for _, capacity := range consumed {
+		if capacity == nil {
+			continue
+		}
+		tableName := aws.StringValue(capacity.TableName)
+		if c.capacities[tableName] == nil {
+			c.capacities[tableName] = &dynamo.ConsumedCapacity{}
+		}
+		addConsumedCapacity(c.capacities[tableName], capacity)
+	}
+}
+
+// of returns the capacity consumed by the table, nil if it consumed none
+func (c *tableCapacities) of(tableName string) *dynamo.ConsumedCapacity {
+	if c == nil {
+		return nil
+	}
+	return c.capacities[tableName]
+}
@@ -45,9 +45,12 @@ type prometheusmetrics struct {
 	mu            sync.RWMutex
 	queryCount    map[string]*prometheus.CounterVec
 	queryDuration map[string]*prometheus.HistogramVec
+	capacityCount *prometheus.CounterVec
 }
 
-var metricLabelNames = []string{statusLabel, tableLabel, sourceLabel}
+var metricLabelNames = []string{statusLabel, tableLabel, sourceLabel, indexLabel}
+
+var capacityLabelNames = []string{tableLabel, indexLabel, opLabel, sourceLabel}
 
 func (m *prometheusmetrics) newCounter(caller string) *prometheus.CounterVec {
 	opts := prometheus.CounterOpts{
@@ -91,11 +94,31 @@ func (m *prometheusmetrics) newHistogramVec(caller string) *prometheus.Histogram
 	return histogram
 }
 
+func (m *prometheusmetrics) newCapacityCounter() *prometheus.CounterVec {
+	opts := prometheus.CounterOpts{
+		Namespace:   m.cfg.Namespace,
+		Subsystem:   m.cfg.Subsystem,
+		Name:        "consumed_capacity_units",
+		Help:        "capacity units consumed by operations; read operations consume read and all others write capacity units",
+		ConstLabels: m.cfg.ConstLabels,
+	}
+	counter := prometheus.NewCounterVec(opts, capacityLabelNames)
+	if err := m.registry.Register(counter); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.CounterVec)
+		}
+		panic(err)
+	}
+	return counter
+}
+
 const (
 	statusLabel = "status"
 	callerLabel = "caller" // NOTE: used separate metrics for now
 	sourceLabel = "source"
 	tableLabel  = "table"
+	indexLabel  = "index"
+	opLabel     = "op"
 )
 
 // NewPrometheusMetrics creates Prometheus metrics with default config.
@@ -155,6 +178,7 @@ func (m *prometheusmetrics) Record(ctx context.Context, caller string, key KeyIn
 	labels := prometheus.Labels{
 		statusLabel: status,
 		tableLabel:  table,
+		indexLabel:  "",
 	}
 	maps.Copy(labels, GetLabelsFromContext(ctx))
 	if labels[sourceLabel] == "" {
@@ -165,6 +189,63 @@ func (m *prometheusmetrics) Record(ctx context.Context, caller string, key KeyIn
 	histogram.With(labels).Observe(duration.Seconds())
 }
 
+// RecordConsumedCapacity adds the consumed capacity to the capacity counter; the capacity of the table and of every
+// secondary index is counted separately, labelled with the index name
+func (m *prometheusmetrics) RecordConsumedCapacity(ctx context.Context, caller string, key KeyInterface, capacity ConsumedCapacity) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg := fmt.Sprintf("prometheus metrics RecordConsumedCapacity panic recovered: caller=%q panic=%v", caller, r)
+			if m.cfg.Log != nil {
+				m.cfg.Log.WithContext(ctx).Error(msg)
+			} else {
+				log.Printf("djoemo: %s", msg)
+			}
+		}
+	}()
+
+	m.mu.RLock()
+	counter := m.capacityCount
+	m.mu.RUnlock()
+
+	if counter == nil {
+		m.mu.Lock()
+		if m.capacityCount == nil {
+			m.capacityCount = m.newCapacityCounter()
+		}
+		counter = m.capacityCount
+		m.mu.Unlock()
+	}
+
+	table := strings.ToLower(capacity.TableName)
+	if key != nil && key.TableName() != "" {
+		table = strings.ToLower(key.TableName())
+	}
+
+	labels := prometheus.Labels{
+		tableLabel: table,
+		opLabel:    caller,
+	}
+	if source := GetLabelsFromContext(ctx)[sourceLabel]; source != "" {
+		labels[sourceLabel] = source
+	} else {
+		labels[sourceLabel] = externalCaller()
+	}
+
+	// without a breakdown by table and indexes only the total is known
+	tableUnits := capacity.Table
+	if tableUnits == 0 && len(capacity.Indexes) == 0 {
+		tableUnits = capacity.Total
+	}
+	if tableUnits > 0 {
+		labels[indexLabel] = ""
+		counter.With(labels).Add(tableUnits)
+	}
+	for index, units := range capacity.Indexes {
+		labels[indexLabel] = index
+		counter.With(labels).Add(units)
+	}
+}
+
 // libraryDir is the directory on disk that contains this library's source files,
 // determined once at init time via runtime.Caller(0). We compare frame file paths
 // against this directory to decide whether a frame belongs to this library.
@@ -132,6 +132,53 @@ var _ = Describe("Prometheus Metrics", func() {
 		})
 	})
 
+	Describe("RecordConsumedCapacity", func() {
+		It("counts the capacity of the table and of every index", func() {
+			ctx := djoemo.WithSourceLabel(context.Background(), "checkout-service")
+			key := djoemo.Key().WithTableName("CartTable").WithHashKeyName("ID").WithHashKey("cart-1")
+			metrics.RecordConsumedCapacity(ctx, djoemo.OpCommit, key, djoemo.ConsumedCapacity{
+				TableName: "CartTable",
+				Total:     3,
+				Table:     1,
+				Indexes:   map[string]float64{"UserIndex": 2},
+			})
+			metrics.RecordConsumedCapacity(ctx, djoemo.OpCommit, key, djoemo.ConsumedCapacity{TableName: "CartTable", Total: 1, Table: 1})
+
+			mfs, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			var capacity *dto.MetricFamily
+			for _, mf := range mfs {
+				if mf.GetName() == "adjoe_djoemo_consumed_capacity_units" {
+					capacity = mf
+				}
+			}
+			Expect(capacity).NotTo(BeNil(), "got: %v", metricFamilyNames(mfs))
+			Expect(capacity.GetMetric()).To(HaveLen(2))
+
+			units := map[string]float64{}
+			for _, metric := range capacity.GetMetric() {
+				Expect(getLabelValue(metric.GetLabel(), "table")).To(Equal("carttable"))
+				Expect(getLabelValue(metric.GetLabel(), "op")).To(Equal(djoemo.OpCommit))
+				Expect(getLabelValue(metric.GetLabel(), "source")).To(Equal("checkout-service"))
+				units[getLabelValue(metric.GetLabel(), "index")] = metric.GetCounter().GetValue()
+			}
+			Expect(units).To(Equal(map[string]float64{"": 2, "UserIndex": 2}))
+		})
+
+		It("counts the total on the table if there is no breakdown", func() {
+			key := djoemo.Key().WithTableName("UserTable").WithHashKeyName("UUID").WithHashKey("id-1")
+			metrics.RecordConsumedCapacity(context.Background(), djoemo.OpRead, key, djoemo.ConsumedCapacity{TableName: "UserTable", Total: 0.5})
+
+			mfs, err := registry.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mfs).To(HaveLen(1))
+			Expect(mfs[0].GetMetric()).To(HaveLen(1))
+			Expect(mfs[0].GetMetric()[0].GetCounter().GetValue()).To(Equal(0.5))
+			Expect(getLabelValue(mfs[0].GetMetric()[0].GetLabel(), "index")).To(Equal(""))
+			Expect(getLabelValue(mfs[0].GetMetric()[0].GetLabel(), "source")).To(MatchRegexp(`^.+\.go:\d+$`))
+		})
+	})
+
 	Describe("Duplicate registration safety", func() {
 		It("does not panic when multiple instances share the same registry", func() {
 			sharedRegistry := prometheus.NewRegistry()
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: checkpoint_store_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=checkpoint_store_interface.go -destination=./mock/checkpoint_store_interface.go -package=mock .
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	djoemo "github.com/adjoeio/djoemo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCheckpointStoreInterface is a mock of CheckpointStoreInterface interface.
+type MockCheckpointStoreInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckpointStoreInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCheckpointStoreInterfaceMockRecorder is the mock recorder for MockCheckpointStoreInterface.
+type MockCheckpointStoreInterfaceMockRecorder struct {
+	mock *MockCheckpointStoreInterface
+}
+
+// NewMockCheckpointStoreInterface creates a new mock instance.
+func NewMockCheckpointStoreInterface(ctrl *gomock.Controller) *MockCheckpointStoreInterface {
+	mock := &MockCheckpointStoreInterface{ctrl: ctrl}
+	mock.recorder = &MockCheckpointStoreInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckpointStoreInterface) EXPECT() *MockCheckpointStoreInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockCheckpointStoreInterface) Delete(ctx context.Context, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCheckpointStoreInterfaceMockRecorder) Delete(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCheckpointStoreInterface)(nil).Delete), ctx, name)
+}
+
+// Load mocks base method.
+func (m *MockCheckpointStoreInterface) Load(ctx context.Context, name string) (djoemo.ScanCheckpoint, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, name)
+	ret0, _ := ret[0].(djoemo.ScanCheckpoint)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockCheckpointStoreInterfaceMockRecorder) Load(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockCheckpointStoreInterface)(nil).Load), ctx, name)
+}
+
+// Save mocks base method.
+func (m *MockCheckpointStoreInterface) Save(ctx context.Context, name string, checkpoint djoemo.ScanCheckpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, name, checkpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockCheckpointStoreInterfaceMockRecorder) Save(ctx, name, checkpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCheckpointStoreInterface)(nil).Save), ctx, name, checkpoint)
+}
@@ -35,9 +35,18 @@ type DynamoMock struct {
 	Conditions                map[string]*dynamodb.Condition
 	InputMatcher              gomock.Matcher
 	Limit                     int64
+	SearchLimit               int64
 	Desc                      bool
 	ConditionExpression       *string
 	ExpressionAttributeValues map[string]*dynamodb.AttributeValue
+	FilterExpression          *string
+	FilterAttributeValues     map[string]*dynamodb.AttributeValue
+	Projection                []string
+	StartKey                  map[string]*dynamodb.AttributeValue
+	LastEvaluatedKey          map[string]*dynamodb.AttributeValue
+	Consistent                bool
+	Count                     bool
+	ConsumedCapacity          bool
 }
 
 // NewDynamoMock Factory for DynamoMock wrapper
@@ -55,6 +64,15 @@ func (d *DynamoMock) Should() *DynamoMock {
 	d.Conditions = nil
 	d.Desc = false
 	d.Limit = 0
+	d.SearchLimit = 0
+	d.FilterExpression = nil
+	d.FilterAttributeValues = nil
+	d.Projection = nil
+	d.StartKey = nil
+	d.LastEvaluatedKey = nil
+	d.Consistent = false
+	d.Count = false
+	d.ConsumedCapacity = false
 	d.InputMatcher = &InputMatcher{}
 	d.Range = make(map[string]*dynamodb.AttributeValue)
 	return d
@@ -81,6 +99,9 @@ func (d *DynamoMock) Query(opts ...DynamoDBOption) *DynamoMock {
 	for _, opt := range opts {
 		opt(d)
 	}
+	if d.QueryOutput != nil && d.LastEvaluatedKey != nil {
+		d.QueryOutput.LastEvaluatedKey = d.LastEvaluatedKey
+	}
 	// todo use with error func in func scope
 	var err error
 	if d.QueryOutput == nil {
@@ -176,13 +197,21 @@ func (d *DynamoMock) WithIndex(name string) DynamoDBOption {
 	}
 }
 
-// WithLimit register option limit
+// WithLimit register option limit; for queries it is the result limit, which is not sent if the query is filtered,
+// for scans it is sent as it is
 func (d *DynamoMock) WithLimit(limit int64) DynamoDBOption {
 	return func(args *DynamoMock) {
 		args.Limit = limit
 	}
 }
 
+// WithSearchLimit register option evaluation limit of queries and scans; it is always sent and takes precedence over the limit
+func (d *DynamoMock) WithSearchLimit(limit int64) DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.SearchLimit = limit
+	}
+}
+
 func (d *DynamoMock) WithGetKeys(keys []map[string]interface{}) DynamoDBOption {
 	return func(args *DynamoMock) {
 		args.BatchGetKeys = keys
@@ -310,6 +339,20 @@ func (d *DynamoMock) WithCondition(field string, value interface{}, operator str
 	}
 }
 
+// WithConditionValues register option key condition comparing against multiple values, e.g. BETWEEN
+func (d *DynamoMock) WithConditionValues(field string, operator string, values ...interface{}) DynamoDBOption {
+	return func(args *DynamoMock) {
+		if args.Conditions == nil {
+			args.Conditions = make(map[string]*dynamodb.Condition)
+		}
+		l, _ := dynamodbattribute.MarshalList(values)
+		args.Conditions[field] = &dynamodb.Condition{
+			AttributeValueList: l,
+			ComparisonOperator: aws.String(operator),
+		}
+	}
+}
+
 // WithConditionExpression register option dynamodb GetItemOutput
 func (d *DynamoMock) WithConditionExpression(expression string, value interface{}) DynamoDBOption {
 	return func(args *DynamoMock) {
@@ -322,6 +365,72 @@ func (d *DynamoMock) WithConditionExpression(expression string, value interface{
 	}
 }
 
+// WithFilterExpression register option query filter expression; expression is given as sent to dynamodb,
+// each ? placeholder is replaced by the matching value
+func (d *DynamoMock) WithFilterExpression(expression string, values ...interface{}) DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.FilterAttributeValues = make(map[string]*dynamodb.AttributeValue)
+		for i, value := range values {
+			expressionAttributeValueField := ":v" + strconv.Itoa(i)
+			expression = strings.Replace(expression, "?", expressionAttributeValueField, 1)
+			av, _ := dynamodbattribute.Marshal(value)
+			args.FilterAttributeValues[expressionAttributeValueField] = av
+		}
+		args.FilterExpression = &expression
+	}
+}
+
+// WithProjection register option projected attributes; get and query expect attribute names that are not reserved words
+func (d *DynamoMock) WithProjection(attributes ...string) DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.Projection = attributes
+	}
+}
+
+// WithStartKey register option key the query starts from, as resumed from a page token
+func (d *DynamoMock) WithStartKey(value map[string]interface{}) DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.StartKey, _ = dynamodbattribute.MarshalMap(value)
+	}
+}
+
+// WithLastEvaluatedKey register option last evaluated key returned with the query output
+func (d *DynamoMock) WithLastEvaluatedKey(value map[string]interface{}) DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.LastEvaluatedKey, _ = dynamodbattribute.MarshalMap(value)
+	}
+}
+
+// WithConsistentRead register option strongly consistent read
+func (d *DynamoMock) WithConsistentRead() DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.Consistent = true
+	}
+}
+
+// WithCount register option count only query or scan, the items are not returned
+func (d *DynamoMock) WithCount() DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.Count = true
+	}
+}
+
+// WithConsumedCapacity register option the consumed capacity of get, query, scan and batch get is requested,
+// as it is if a metrics publisher records consumed capacity
+func (d *DynamoMock) WithConsumedCapacity() DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.ConsumedCapacity = true
+	}
+}
+
+// WithCountOutput register option number of items counted by a query and a scan
+func (d *DynamoMock) WithCountOutput(count int64) DynamoDBOption {
+	return func(args *DynamoMock) {
+		args.QueryOutput = &dynamodb.QueryOutput{Count: aws.Int64(count)}
+		args.ScanAllOutput = &dynamodb.ScanOutput{Count: aws.Int64(count)}
+	}
+}
+
 // WithQueryOutput register option dynamodb GetItemOutput
 func (d *DynamoMock) WithQueryOutput(value interface{}) DynamoDBOption {
 	return func(args *DynamoMock) {
@@ -448,6 +557,15 @@ func (d *DynamoMock) getItemInput() *dynamodb.GetItemInput {
 		TableName: aws.String(d.TableName),
 		Key:       d.Hash,
 	}
+	if d.Consistent {
+		req.ConsistentRead = aws.Bool(true)
+	}
+	if len(d.Projection) > 0 {
+		req.ProjectionExpression = aws.String(strings.Join(d.Projection, ", "))
+	}
+	if d.ConsumedCapacity {
+		req.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityIndexes)
+	}
 	return req
 }
 
@@ -460,12 +578,37 @@ func (d *DynamoMock) queryInput() *dynamodb.QueryInput {
 	if d.Index != "" {
 		req.IndexName = aws.String(d.Index)
 	}
-	if d.Limit != 0 {
+	// a filtered query is paginated until the limit is reached, so the limit is not sent
+	if d.Limit != 0 && d.FilterExpression == nil {
 		req.Limit = aws.Int64(d.Limit)
 	}
+	if d.SearchLimit != 0 {
+		req.Limit = aws.Int64(d.SearchLimit)
+	}
+	if d.FilterExpression != nil {
+		req.FilterExpression = d.FilterExpression
+		if len(d.FilterAttributeValues) > 0 {
+			req.ExpressionAttributeValues = d.FilterAttributeValues
+		}
+	}
 	if d.Desc {
 		req.ScanIndexForward = aws.Bool(false)
 	}
+	if len(d.Projection) > 0 {
+		req.ProjectionExpression = aws.String(strings.Join(d.Projection, ", "))
+	}
+	if d.StartKey != nil {
+		req.ExclusiveStartKey = d.StartKey
+	}
+	if d.Consistent {
+		req.ConsistentRead = aws.Bool(true)
+	}
+	if d.Count {
+		req.Select = aws.String(dynamodb.SelectCount)
+	}
+	if d.ConsumedCapacity {
+		req.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityIndexes)
+	}
 
 	return req
 }
@@ -485,24 +628,53 @@ func (d *DynamoMock) batchGetInput() *dynamodb.BatchGetItemInput {
 	req := &dynamodb.BatchGetItemInput{
 		RequestItems: map[string]*dynamodb.KeysAndAttributes{
 			d.TableName: {
-				ConsistentRead: aws.Bool(false),
+				ConsistentRead: aws.Bool(d.Consistent),
 				Keys:           keys,
 			},
 		},
 	}
+	// projected batch gets replace every attribute name by a placeholder
+	if len(d.Projection) > 0 {
+		kas := req.RequestItems[d.TableName]
+		var paths []string
+		kas.ExpressionAttributeNames = make(map[string]*string)
+		for i, attribute := range d.Projection {
+			placeholder := "#p" + strconv.Itoa(i)
+			kas.ExpressionAttributeNames[placeholder] = aws.String(attribute)
+			paths = append(paths, placeholder)
+		}
+		kas.ProjectionExpression = aws.String(strings.Join(paths, ", "))
+	}
+	if d.ConsumedCapacity {
+		req.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityIndexes)
+	}
 
 	return req
 }
 
 func (d *DynamoMock) scanInput() *dynamodb.ScanInput {
-	b := false
 	req := &dynamodb.ScanInput{
 		TableName:      aws.String(d.TableName),
-		ConsistentRead: &b,
+		ConsistentRead: aws.Bool(d.Consistent),
 	}
 	if d.Limit != 0 {
 		req.Limit = aws.Int64(d.Limit)
 	}
+	if d.SearchLimit != 0 {
+		req.Limit = aws.Int64(d.SearchLimit)
+	}
+	if d.FilterExpression != nil {
+		req.FilterExpression = d.FilterExpression
+		if len(d.FilterAttributeValues) > 0 {
+			req.ExpressionAttributeValues = d.FilterAttributeValues
+		}
+	}
+	if d.Count {
+		req.Select = aws.String(dynamodb.SelectCount)
+	}
+	if d.ConsumedCapacity {
+		req.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityIndexes)
+	}
 	return req
 }
 
@@ -42,6 +42,21 @@ func (m *MockGlobalIndexInterface) EXPECT() *MockGlobalIndexInterfaceMockRecorde
 	return m.recorder
 }
 
+// CountWithContext mocks base method.
+func (m *MockGlobalIndexInterface) CountWithContext(ctx context.Context, query djoemo.QueryInterface) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithContext", ctx, query)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithContext indicates an expected call of CountWithContext.
+func (mr *MockGlobalIndexInterfaceMockRecorder) CountWithContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).CountWithContext), ctx, query)
+}
+
 // GetItemWithContext mocks base method.
 func (m *MockGlobalIndexInterface) GetItemWithContext(ctx context.Context, key djoemo.KeyInterface, item any) (bool, error) {
 	m.ctrl.T.Helper()
@@ -87,6 +102,64 @@ func (mr *MockGlobalIndexInterfaceMockRecorder) GetItemsWithRangeWithContext(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsWithRangeWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).GetItemsWithRangeWithContext), ctx, key, items)
 }
 
+// MultiQueryWithContext mocks base method.
+func (m *MockGlobalIndexInterface) MultiQueryWithContext(ctx context.Context, multiQuery djoemo.MultiQueryInterface, items any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiQueryWithContext", ctx, multiQuery, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MultiQueryWithContext indicates an expected call of MultiQueryWithContext.
+func (mr *MockGlobalIndexInterfaceMockRecorder) MultiQueryWithContext(ctx, multiQuery, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiQueryWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).MultiQueryWithContext), ctx, multiQuery, items)
+}
+
+// QueryHydratedWithContext mocks base method.
+func (m *MockGlobalIndexInterface) QueryHydratedWithContext(ctx context.Context, query djoemo.QueryInterface, table djoemo.TableSpec, items any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryHydratedWithContext", ctx, query, table, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueryHydratedWithContext indicates an expected call of QueryHydratedWithContext.
+func (mr *MockGlobalIndexInterfaceMockRecorder) QueryHydratedWithContext(ctx, query, table, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryHydratedWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).QueryHydratedWithContext), ctx, query, table, items)
+}
+
+// QueryIteratorWithContext mocks base method.
+func (m *MockGlobalIndexInterface) QueryIteratorWithContext(ctx context.Context, query djoemo.QueryInterface) (djoemo.QueryIteratorInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryIteratorWithContext", ctx, query)
+	ret0, _ := ret[0].(djoemo.QueryIteratorInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryIteratorWithContext indicates an expected call of QueryIteratorWithContext.
+func (mr *MockGlobalIndexInterfaceMockRecorder) QueryIteratorWithContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIteratorWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).QueryIteratorWithContext), ctx, query)
+}
+
+// QueryPageWithContext mocks base method.
+func (m *MockGlobalIndexInterface) QueryPageWithContext(ctx context.Context, query djoemo.QueryInterface, items any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPageWithContext", ctx, query, items)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPageWithContext indicates an expected call of QueryPageWithContext.
+func (mr *MockGlobalIndexInterfaceMockRecorder) QueryPageWithContext(ctx, query, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPageWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).QueryPageWithContext), ctx, query, items)
+}
+
 // QueryWithContext mocks base method.
 func (m *MockGlobalIndexInterface) QueryWithContext(ctx context.Context, query djoemo.QueryInterface, item any) error {
 	m.ctrl.T.Helper()
@@ -101,6 +174,26 @@ func (mr *MockGlobalIndexInterfaceMockRecorder) QueryWithContext(ctx, query, ite
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).QueryWithContext), ctx, query, item)
 }
 
+// ScanIteratorWithContext mocks base method.
+func (m *MockGlobalIndexInterface) ScanIteratorWithContext(ctx context.Context, key djoemo.KeyInterface, searchLimit int64, opts ...djoemo.ScanOption) (djoemo.ScanIteratorInterface, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, searchLimit}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScanIteratorWithContext", varargs...)
+	ret0, _ := ret[0].(djoemo.ScanIteratorInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanIteratorWithContext indicates an expected call of ScanIteratorWithContext.
+func (mr *MockGlobalIndexInterfaceMockRecorder) ScanIteratorWithContext(ctx, key, searchLimit any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, searchLimit}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanIteratorWithContext", reflect.TypeOf((*MockGlobalIndexInterface)(nil).ScanIteratorWithContext), varargs...)
+}
+
 // WithLog mocks base method.
 func (m *MockGlobalIndexInterface) WithLog(log djoemo.LogInterface) {
 	m.ctrl.T.Helper()
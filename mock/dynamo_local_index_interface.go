@@ -0,0 +1,199 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dynamo_local_index_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=dynamo_local_index_interface.go -destination=./mock/dynamo_local_index_interface.go -package=mock .
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	djoemo "github.com/adjoeio/djoemo"
+	prometheus "github.com/prometheus/client_golang/prometheus"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLocalIndexInterface is a mock of LocalIndexInterface interface.
+type MockLocalIndexInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLocalIndexInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockLocalIndexInterfaceMockRecorder is the mock recorder for MockLocalIndexInterface.
+type MockLocalIndexInterfaceMockRecorder struct {
+	mock *MockLocalIndexInterface
+}
+
+// NewMockLocalIndexInterface creates a new mock instance.
+func NewMockLocalIndexInterface(ctrl *gomock.Controller) *MockLocalIndexInterface {
+	mock := &MockLocalIndexInterface{ctrl: ctrl}
+	mock.recorder = &MockLocalIndexInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLocalIndexInterface) EXPECT() *MockLocalIndexInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CountWithContext mocks base method.
+func (m *MockLocalIndexInterface) CountWithContext(ctx context.Context, query djoemo.QueryInterface) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithContext", ctx, query)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithContext indicates an expected call of CountWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) CountWithContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).CountWithContext), ctx, query)
+}
+
+// GetItemWithContext mocks base method.
+func (m *MockLocalIndexInterface) GetItemWithContext(ctx context.Context, key djoemo.KeyInterface, item any) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemWithContext", ctx, key, item)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemWithContext indicates an expected call of GetItemWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) GetItemWithContext(ctx, key, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).GetItemWithContext), ctx, key, item)
+}
+
+// GetItemsWithContext mocks base method.
+func (m *MockLocalIndexInterface) GetItemsWithContext(ctx context.Context, key djoemo.KeyInterface, items any) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemsWithContext", ctx, key, items)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemsWithContext indicates an expected call of GetItemsWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) GetItemsWithContext(ctx, key, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).GetItemsWithContext), ctx, key, items)
+}
+
+// GetItemsWithRangeWithContext mocks base method.
+func (m *MockLocalIndexInterface) GetItemsWithRangeWithContext(ctx context.Context, key djoemo.KeyInterface, items any) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemsWithRangeWithContext", ctx, key, items)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemsWithRangeWithContext indicates an expected call of GetItemsWithRangeWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) GetItemsWithRangeWithContext(ctx, key, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsWithRangeWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).GetItemsWithRangeWithContext), ctx, key, items)
+}
+
+// MultiQueryWithContext mocks base method.
+func (m *MockLocalIndexInterface) MultiQueryWithContext(ctx context.Context, multiQuery djoemo.MultiQueryInterface, items any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiQueryWithContext", ctx, multiQuery, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MultiQueryWithContext indicates an expected call of MultiQueryWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) MultiQueryWithContext(ctx, multiQuery, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiQueryWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).MultiQueryWithContext), ctx, multiQuery, items)
+}
+
+// QueryIteratorWithContext mocks base method.
+func (m *MockLocalIndexInterface) QueryIteratorWithContext(ctx context.Context, query djoemo.QueryInterface) (djoemo.QueryIteratorInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryIteratorWithContext", ctx, query)
+	ret0, _ := ret[0].(djoemo.QueryIteratorInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryIteratorWithContext indicates an expected call of QueryIteratorWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) QueryIteratorWithContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIteratorWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).QueryIteratorWithContext), ctx, query)
+}
+
+// QueryPageWithContext mocks base method.
+func (m *MockLocalIndexInterface) QueryPageWithContext(ctx context.Context, query djoemo.QueryInterface, items any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPageWithContext", ctx, query, items)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPageWithContext indicates an expected call of QueryPageWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) QueryPageWithContext(ctx, query, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPageWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).QueryPageWithContext), ctx, query, items)
+}
+
+// QueryWithContext mocks base method.
+func (m *MockLocalIndexInterface) QueryWithContext(ctx context.Context, query djoemo.QueryInterface, item any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryWithContext", ctx, query, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueryWithContext indicates an expected call of QueryWithContext.
+func (mr *MockLocalIndexInterfaceMockRecorder) QueryWithContext(ctx, query, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryWithContext", reflect.TypeOf((*MockLocalIndexInterface)(nil).QueryWithContext), ctx, query, item)
+}
+
+// WithLog mocks base method.
+func (m *MockLocalIndexInterface) WithLog(log djoemo.LogInterface) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WithLog", log)
+}
+
+// WithLog indicates an expected call of WithLog.
+func (mr *MockLocalIndexInterfaceMockRecorder) WithLog(log any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLog", reflect.TypeOf((*MockLocalIndexInterface)(nil).WithLog), log)
+}
+
+// WithMetrics mocks base method.
+func (m *MockLocalIndexInterface) WithMetrics(metricsInterface djoemo.MetricsInterface) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "WithMetrics", metricsInterface)
+}
+
+// WithMetrics indicates an expected call of WithMetrics.
+func (mr *MockLocalIndexInterfaceMockRecorder) WithMetrics(metricsInterface any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMetrics", reflect.TypeOf((*MockLocalIndexInterface)(nil).WithMetrics), metricsInterface)
+}
+
+// WithPrometheusMetrics mocks base method.
+func (m *MockLocalIndexInterface) WithPrometheusMetrics(registry *prometheus.Registry, cfg *djoemo.PrometheusConfig) djoemo.LocalIndexInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithPrometheusMetrics", registry, cfg)
+	ret0, _ := ret[0].(djoemo.LocalIndexInterface)
+	return ret0
+}
+
+// WithPrometheusMetrics indicates an expected call of WithPrometheusMetrics.
+func (mr *MockLocalIndexInterfaceMockRecorder) WithPrometheusMetrics(registry, cfg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithPrometheusMetrics", reflect.TypeOf((*MockLocalIndexInterface)(nil).WithPrometheusMetrics), registry, cfg)
+}
@@ -97,6 +97,21 @@ func (mr *MockRepositoryInterfaceMockRecorder) ConditionalUpdateWithUpdateExpres
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConditionalUpdateWithUpdateExpressionsAndReturnValue", reflect.TypeOf((*MockRepositoryInterface)(nil).ConditionalUpdateWithUpdateExpressionsAndReturnValue), varargs...)
 }
 
+// CountWithContext mocks base method.
+func (m *MockRepositoryInterface) CountWithContext(ctx context.Context, query djoemo.QueryInterface) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWithContext", ctx, query)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWithContext indicates an expected call of CountWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) CountWithContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).CountWithContext), ctx, query)
+}
+
 // DeleteItemWithContext mocks base method.
 func (m *MockRepositoryInterface) DeleteItemWithContext(ctx context.Context, key djoemo.KeyInterface) error {
 	m.ctrl.T.Helper()
@@ -169,6 +184,34 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetItemsWithContext(ctx, key, out
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemsWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).GetItemsWithContext), ctx, key, out)
 }
 
+// LIndex mocks base method.
+func (m *MockRepositoryInterface) LIndex(name string) djoemo.LocalIndexInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LIndex", name)
+	ret0, _ := ret[0].(djoemo.LocalIndexInterface)
+	return ret0
+}
+
+// LIndex indicates an expected call of LIndex.
+func (mr *MockRepositoryInterfaceMockRecorder) LIndex(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LIndex", reflect.TypeOf((*MockRepositoryInterface)(nil).LIndex), name)
+}
+
+// MultiQueryWithContext mocks base method.
+func (m *MockRepositoryInterface) MultiQueryWithContext(ctx context.Context, multiQuery djoemo.MultiQueryInterface, items any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MultiQueryWithContext", ctx, multiQuery, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MultiQueryWithContext indicates an expected call of MultiQueryWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) MultiQueryWithContext(ctx, multiQuery, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MultiQueryWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).MultiQueryWithContext), ctx, multiQuery, items)
+}
+
 // OptimisticLockSaveWithContext mocks base method.
 func (m *MockRepositoryInterface) OptimisticLockSaveWithContext(ctx context.Context, key djoemo.KeyInterface, item any) (bool, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +227,74 @@ func (mr *MockRepositoryInterfaceMockRecorder) OptimisticLockSaveWithContext(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OptimisticLockSaveWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).OptimisticLockSaveWithContext), ctx, key, item)
 }
 
+// ParallelScanChannelWithContext mocks base method.
+func (m *MockRepositoryInterface) ParallelScanChannelWithContext(ctx context.Context, key djoemo.KeyInterface, totalSegments int, items chan<- djoemo.ScanItem, opts ...djoemo.ScanOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, totalSegments, items}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ParallelScanChannelWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ParallelScanChannelWithContext indicates an expected call of ParallelScanChannelWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) ParallelScanChannelWithContext(ctx, key, totalSegments, items any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, totalSegments, items}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParallelScanChannelWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).ParallelScanChannelWithContext), varargs...)
+}
+
+// ParallelScanWithContext mocks base method.
+func (m *MockRepositoryInterface) ParallelScanWithContext(ctx context.Context, key djoemo.KeyInterface, totalSegments int, handle func(context.Context, djoemo.ScanItem) error, opts ...djoemo.ScanOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, totalSegments, handle}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ParallelScanWithContext", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ParallelScanWithContext indicates an expected call of ParallelScanWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) ParallelScanWithContext(ctx, key, totalSegments, handle any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, totalSegments, handle}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParallelScanWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).ParallelScanWithContext), varargs...)
+}
+
+// QueryIteratorWithContext mocks base method.
+func (m *MockRepositoryInterface) QueryIteratorWithContext(ctx context.Context, query djoemo.QueryInterface) (djoemo.QueryIteratorInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryIteratorWithContext", ctx, query)
+	ret0, _ := ret[0].(djoemo.QueryIteratorInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryIteratorWithContext indicates an expected call of QueryIteratorWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) QueryIteratorWithContext(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryIteratorWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).QueryIteratorWithContext), ctx, query)
+}
+
+// QueryPageWithContext mocks base method.
+func (m *MockRepositoryInterface) QueryPageWithContext(ctx context.Context, query djoemo.QueryInterface, items any) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryPageWithContext", ctx, query, items)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryPageWithContext indicates an expected call of QueryPageWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) QueryPageWithContext(ctx, query, items any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryPageWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).QueryPageWithContext), ctx, query, items)
+}
+
 // QueryWithContext mocks base method.
 func (m *MockRepositoryInterface) QueryWithContext(ctx context.Context, query djoemo.QueryInterface, item any) error {
 	m.ctrl.T.Helper()
@@ -226,19 +337,87 @@ func (mr *MockRepositoryInterfaceMockRecorder) SaveItemsWithContext(ctx, key, it
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveItemsWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).SaveItemsWithContext), ctx, key, items)
 }
 
+// ScanCountWithContext mocks base method.
+func (m *MockRepositoryInterface) ScanCountWithContext(ctx context.Context, key djoemo.KeyInterface, filter string, args ...any) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, filter}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScanCountWithContext", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanCountWithContext indicates an expected call of ScanCountWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) ScanCountWithContext(ctx, key, filter any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, filter}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanCountWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).ScanCountWithContext), varargs...)
+}
+
 // ScanIteratorWithContext mocks base method.
-func (m *MockRepositoryInterface) ScanIteratorWithContext(ctx context.Context, key djoemo.KeyInterface, searchLimit int64) (djoemo.IteratorInterface, error) {
+func (m *MockRepositoryInterface) ScanIteratorWithContext(ctx context.Context, key djoemo.KeyInterface, searchLimit int64, opts ...djoemo.ScanOption) (djoemo.ScanIteratorInterface, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ScanIteratorWithContext", ctx, key, searchLimit)
-	ret0, _ := ret[0].(djoemo.IteratorInterface)
+	varargs := []any{ctx, key, searchLimit}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ScanIteratorWithContext", varargs...)
+	ret0, _ := ret[0].(djoemo.ScanIteratorInterface)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ScanIteratorWithContext indicates an expected call of ScanIteratorWithContext.
-func (mr *MockRepositoryInterfaceMockRecorder) ScanIteratorWithContext(ctx, key, searchLimit any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder) ScanIteratorWithContext(ctx, key, searchLimit any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, searchLimit}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanIteratorWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).ScanIteratorWithContext), varargs...)
+}
+
+// TransactGetItemsWithContext mocks base method.
+func (m *MockRepositoryInterface) TransactGetItemsWithContext(ctx context.Context, transaction djoemo.TransactGetInterface) ([]djoemo.KeyInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactGetItemsWithContext", ctx, transaction)
+	ret0, _ := ret[0].([]djoemo.KeyInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactGetItemsWithContext indicates an expected call of TransactGetItemsWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) TransactGetItemsWithContext(ctx, transaction any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanIteratorWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).ScanIteratorWithContext), ctx, key, searchLimit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactGetItemsWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).TransactGetItemsWithContext), ctx, transaction)
+}
+
+// TransactWriteItemsWithContext mocks base method.
+func (m *MockRepositoryInterface) TransactWriteItemsWithContext(ctx context.Context, transaction djoemo.TransactWriteInterface) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactWriteItemsWithContext", ctx, transaction)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransactWriteItemsWithContext indicates an expected call of TransactWriteItemsWithContext.
+func (mr *MockRepositoryInterfaceMockRecorder) TransactWriteItemsWithContext(ctx, transaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactWriteItemsWithContext", reflect.TypeOf((*MockRepositoryInterface)(nil).TransactWriteItemsWithContext), ctx, transaction)
+}
+
+// UnitOfWork mocks base method.
+func (m *MockRepositoryInterface) UnitOfWork() djoemo.UnitOfWorkInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnitOfWork")
+	ret0, _ := ret[0].(djoemo.UnitOfWorkInterface)
+	return ret0
+}
+
+// UnitOfWork indicates an expected call of UnitOfWork.
+func (mr *MockRepositoryInterfaceMockRecorder) UnitOfWork() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnitOfWork", reflect.TypeOf((*MockRepositoryInterface)(nil).UnitOfWork))
 }
 
 // UpdateWithContext mocks base method.
@@ -283,6 +462,22 @@ func (mr *MockRepositoryInterfaceMockRecorder) UpdateWithUpdateExpressionsAndRet
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWithUpdateExpressionsAndReturnValue", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateWithUpdateExpressionsAndReturnValue), ctx, key, item, updateExpressions)
 }
 
+// WithConsistentReadTables mocks base method.
+func (m *MockRepositoryInterface) WithConsistentReadTables(tableNames ...string) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range tableNames {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "WithConsistentReadTables", varargs...)
+}
+
+// WithConsistentReadTables indicates an expected call of WithConsistentReadTables.
+func (mr *MockRepositoryInterfaceMockRecorder) WithConsistentReadTables(tableNames ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithConsistentReadTables", reflect.TypeOf((*MockRepositoryInterface)(nil).WithConsistentReadTables), tableNames...)
+}
+
 // WithLog mocks base method.
 func (m *MockRepositoryInterface) WithLog(log djoemo.LogInterface) {
 	m.ctrl.T.Helper()
@@ -307,6 +502,20 @@ func (mr *MockRepositoryInterfaceMockRecorder) WithMetrics(metricsInterface any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithMetrics", reflect.TypeOf((*MockRepositoryInterface)(nil).WithMetrics), metricsInterface)
 }
 
+// WithPageTokenCodec mocks base method.
+func (m *MockRepositoryInterface) WithPageTokenCodec(codec djoemo.PageTokenCodecInterface) djoemo.RepositoryInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithPageTokenCodec", codec)
+	ret0, _ := ret[0].(djoemo.RepositoryInterface)
+	return ret0
+}
+
+// WithPageTokenCodec indicates an expected call of WithPageTokenCodec.
+func (mr *MockRepositoryInterfaceMockRecorder) WithPageTokenCodec(codec any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithPageTokenCodec", reflect.TypeOf((*MockRepositoryInterface)(nil).WithPageTokenCodec), codec)
+}
+
 // WithPrometheusMetrics mocks base method.
 func (m *MockRepositoryInterface) WithPrometheusMetrics(registry *prometheus.Registry, cfg *djoemo.PrometheusConfig) djoemo.RepositoryInterface {
 	m.ctrl.T.Helper()
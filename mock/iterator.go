@@ -0,0 +1,187 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: iterator.go
+//
+// Generated by this command:
+//
+//	mockgen -source=iterator.go -destination=./mock/iterator.go -package=mock .
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	djoemo "github.com/adjoeio/djoemo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIteratorInterface is a mock of IteratorInterface interface.
+type MockIteratorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockIteratorInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockIteratorInterfaceMockRecorder is the mock recorder for MockIteratorInterface.
+type MockIteratorInterfaceMockRecorder struct {
+	mock *MockIteratorInterface
+}
+
+// NewMockIteratorInterface creates a new mock instance.
+func NewMockIteratorInterface(ctrl *gomock.Controller) *MockIteratorInterface {
+	mock := &MockIteratorInterface{ctrl: ctrl}
+	mock.recorder = &MockIteratorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIteratorInterface) EXPECT() *MockIteratorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method.
+func (m *MockIteratorInterface) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockIteratorInterfaceMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockIteratorInterface)(nil).Err))
+}
+
+// NextItem mocks base method.
+func (m *MockIteratorInterface) NextItem(out any) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextItem", out)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NextItem indicates an expected call of NextItem.
+func (mr *MockIteratorInterfaceMockRecorder) NextItem(out any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextItem", reflect.TypeOf((*MockIteratorInterface)(nil).NextItem), out)
+}
+
+// MockScanIteratorInterface is a mock of ScanIteratorInterface interface.
+type MockScanIteratorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockScanIteratorInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockScanIteratorInterfaceMockRecorder is the mock recorder for MockScanIteratorInterface.
+type MockScanIteratorInterfaceMockRecorder struct {
+	mock *MockScanIteratorInterface
+}
+
+// NewMockScanIteratorInterface creates a new mock instance.
+func NewMockScanIteratorInterface(ctrl *gomock.Controller) *MockScanIteratorInterface {
+	mock := &MockScanIteratorInterface{ctrl: ctrl}
+	mock.recorder = &MockScanIteratorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScanIteratorInterface) EXPECT() *MockScanIteratorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Checkpoint mocks base method.
+func (m *MockScanIteratorInterface) Checkpoint() djoemo.ScanCheckpoint {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkpoint")
+	ret0, _ := ret[0].(djoemo.ScanCheckpoint)
+	return ret0
+}
+
+// Checkpoint indicates an expected call of Checkpoint.
+func (mr *MockScanIteratorInterfaceMockRecorder) Checkpoint() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkpoint", reflect.TypeOf((*MockScanIteratorInterface)(nil).Checkpoint))
+}
+
+// Err mocks base method.
+func (m *MockScanIteratorInterface) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockScanIteratorInterfaceMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockScanIteratorInterface)(nil).Err))
+}
+
+// NextItem mocks base method.
+func (m *MockScanIteratorInterface) NextItem(out any) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextItem", out)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NextItem indicates an expected call of NextItem.
+func (mr *MockScanIteratorInterfaceMockRecorder) NextItem(out any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextItem", reflect.TypeOf((*MockScanIteratorInterface)(nil).NextItem), out)
+}
+
+// MockQueryIteratorInterface is a mock of QueryIteratorInterface interface.
+type MockQueryIteratorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockQueryIteratorInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockQueryIteratorInterfaceMockRecorder is the mock recorder for MockQueryIteratorInterface.
+type MockQueryIteratorInterfaceMockRecorder struct {
+	mock *MockQueryIteratorInterface
+}
+
+// NewMockQueryIteratorInterface creates a new mock instance.
+func NewMockQueryIteratorInterface(ctrl *gomock.Controller) *MockQueryIteratorInterface {
+	mock := &MockQueryIteratorInterface{ctrl: ctrl}
+	mock.recorder = &MockQueryIteratorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQueryIteratorInterface) EXPECT() *MockQueryIteratorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method.
+func (m *MockQueryIteratorInterface) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockQueryIteratorInterfaceMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockQueryIteratorInterface)(nil).Err))
+}
+
+// NextItem mocks base method.
+func (m *MockQueryIteratorInterface) NextItem(out any) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextItem", out)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NextItem indicates an expected call of NextItem.
+func (mr *MockQueryIteratorInterfaceMockRecorder) NextItem(out any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextItem", reflect.TypeOf((*MockQueryIteratorInterface)(nil).NextItem), out)
+}
@@ -53,3 +53,39 @@ func (mr *MockMetricsInterfaceMockRecorder) Record(ctx, caller, key, duration, s
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockMetricsInterface)(nil).Record), ctx, caller, key, duration, success)
 }
+
+// MockCapacityMetricsInterface is a mock of CapacityMetricsInterface interface.
+type MockCapacityMetricsInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCapacityMetricsInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCapacityMetricsInterfaceMockRecorder is the mock recorder for MockCapacityMetricsInterface.
+type MockCapacityMetricsInterfaceMockRecorder struct {
+	mock *MockCapacityMetricsInterface
+}
+
+// NewMockCapacityMetricsInterface creates a new mock instance.
+func NewMockCapacityMetricsInterface(ctrl *gomock.Controller) *MockCapacityMetricsInterface {
+	mock := &MockCapacityMetricsInterface{ctrl: ctrl}
+	mock.recorder = &MockCapacityMetricsInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCapacityMetricsInterface) EXPECT() *MockCapacityMetricsInterfaceMockRecorder {
+	return m.recorder
+}
+
+// RecordConsumedCapacity mocks base method.
+func (m *MockCapacityMetricsInterface) RecordConsumedCapacity(ctx context.Context, caller string, key djoemo.KeyInterface, capacity djoemo.ConsumedCapacity) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordConsumedCapacity", ctx, caller, key, capacity)
+}
+
+// RecordConsumedCapacity indicates an expected call of RecordConsumedCapacity.
+func (mr *MockCapacityMetricsInterfaceMockRecorder) RecordConsumedCapacity(ctx, caller, key, capacity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordConsumedCapacity", reflect.TypeOf((*MockCapacityMetricsInterface)(nil).RecordConsumedCapacity), ctx, caller, key, capacity)
+}
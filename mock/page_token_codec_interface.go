@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: page_token_codec_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=page_token_codec_interface.go -destination=./mock/page_token_codec_interface.go -package=mock .
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	djoemo "github.com/adjoeio/djoemo"
+	dynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPageTokenCodecInterface is a mock of PageTokenCodecInterface interface.
+type MockPageTokenCodecInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPageTokenCodecInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockPageTokenCodecInterfaceMockRecorder is the mock recorder for MockPageTokenCodecInterface.
+type MockPageTokenCodecInterfaceMockRecorder struct {
+	mock *MockPageTokenCodecInterface
+}
+
+// NewMockPageTokenCodecInterface creates a new mock instance.
+func NewMockPageTokenCodecInterface(ctrl *gomock.Controller) *MockPageTokenCodecInterface {
+	mock := &MockPageTokenCodecInterface{ctrl: ctrl}
+	mock.recorder = &MockPageTokenCodecInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPageTokenCodecInterface) EXPECT() *MockPageTokenCodecInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Decode mocks base method.
+func (m *MockPageTokenCodecInterface) Decode(scope djoemo.PageTokenScope, token string) (map[string]*dynamodb.AttributeValue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", scope, token)
+	ret0, _ := ret[0].(map[string]*dynamodb.AttributeValue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decode indicates an expected call of Decode.
+func (mr *MockPageTokenCodecInterfaceMockRecorder) Decode(scope, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockPageTokenCodecInterface)(nil).Decode), scope, token)
+}
+
+// Encode mocks base method.
+func (m *MockPageTokenCodecInterface) Encode(scope djoemo.PageTokenScope, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", scope, lastEvaluatedKey)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockPageTokenCodecInterfaceMockRecorder) Encode(scope, lastEvaluatedKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockPageTokenCodecInterface)(nil).Encode), scope, lastEvaluatedKey)
+}
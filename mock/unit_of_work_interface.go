@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: unit_of_work_interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=unit_of_work_interface.go -destination=./mock/unit_of_work_interface.go -package=mock .
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	djoemo "github.com/adjoeio/djoemo"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUnitOfWorkInterface is a mock of UnitOfWorkInterface interface.
+type MockUnitOfWorkInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockUnitOfWorkInterfaceMockRecorder is the mock recorder for MockUnitOfWorkInterface.
+type MockUnitOfWorkInterfaceMockRecorder struct {
+	mock *MockUnitOfWorkInterface
+}
+
+// NewMockUnitOfWorkInterface creates a new mock instance.
+func NewMockUnitOfWorkInterface(ctrl *gomock.Controller) *MockUnitOfWorkInterface {
+	mock := &MockUnitOfWorkInterface{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWorkInterface) EXPECT() *MockUnitOfWorkInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CommitWithContext mocks base method.
+func (m *MockUnitOfWorkInterface) CommitWithContext(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommitWithContext", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitWithContext indicates an expected call of CommitWithContext.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) CommitWithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitWithContext", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).CommitWithContext), ctx)
+}
+
+// RegisterDeleted mocks base method.
+func (m *MockUnitOfWorkInterface) RegisterDeleted(key djoemo.KeyInterface, item djoemo.ModelInterface) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterDeleted", key, item)
+}
+
+// RegisterDeleted indicates an expected call of RegisterDeleted.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) RegisterDeleted(key, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterDeleted", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).RegisterDeleted), key, item)
+}
+
+// RegisterLoaded mocks base method.
+func (m *MockUnitOfWorkInterface) RegisterLoaded(key djoemo.KeyInterface, item djoemo.ModelInterface) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterLoaded", key, item)
+}
+
+// RegisterLoaded indicates an expected call of RegisterLoaded.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) RegisterLoaded(key, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterLoaded", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).RegisterLoaded), key, item)
+}
+
+// RegisterNew mocks base method.
+func (m *MockUnitOfWorkInterface) RegisterNew(key djoemo.KeyInterface, item djoemo.ModelInterface) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterNew", key, item)
+}
+
+// RegisterNew indicates an expected call of RegisterNew.
+func (mr *MockUnitOfWorkInterfaceMockRecorder) RegisterNew(key, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterNew", reflect.TypeOf((*MockUnitOfWorkInterface)(nil).RegisterNew), key, item)
+}
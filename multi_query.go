@@ -0,0 +1,295 @@
+package djoemo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// defaultMultiQueryConcurrency is the number of queries of a multi query that run at the same time by default
+const defaultMultiQueryConcurrency = 10
+
+type multiQuery struct {
+	queries      []QueryInterface
+	rangeKeyName *string
+	concurrency  int
+	limit        *int64
+}
+
+// MultiQuery factory method to create struct that implements multi query interface
+func MultiQuery(queries ...QueryInterface) *multiQuery {
+	return &multiQuery{
+		queries:     queries,
+		concurrency: defaultMultiQueryConcurrency,
+	}
+}
+
+// WithQuery adds a query to the multi query
+func (mq *multiQuery) WithQuery(query QueryInterface) *multiQuery {
+	mq.queries = append(mq.queries, query)
+	return mq
+}
+
+// WithRangeKeyName set the name of the range key the items are merged by; by default the range key name of the queries is used
+func (mq *multiQuery) WithRangeKeyName(rangeKeyName string) *multiQuery {
+	mq.rangeKeyName = &rangeKeyName
+	return mq
+}
+
+// WithConcurrency set the number of queries that run at the same time
+func (mq *multiQuery) WithConcurrency(concurrency int) *multiQuery {
+	mq.concurrency = concurrency
+	return mq
+}
+
+// WithLimit set the maximum number of merged items
+func (mq *multiQuery) WithLimit(limit int64) *multiQuery {
+	mq.limit = &limit
+	return mq
+}
+
+// Queries returns the queries in the order they were added
+func (mq *multiQuery) Queries() []QueryInterface {
+	return mq.queries
+}
+
+// RangeKeyName returns the name of the range key the items are merged by
+func (mq *multiQuery) RangeKeyName() *string {
+	return mq.rangeKeyName
+}
+
+// Concurrency returns the number of queries that run at the same time
+func (mq *multiQuery) Concurrency() int {
+	return mq.concurrency
+}
+
+// Limit returns the maximum number of merged items
+func (mq *multiQuery) Limit() *int64 {
+	return mq.limit
+}
+
+// MultiQueryFailure is a query of a multi query that failed
+type MultiQueryFailure struct {
+	// Index is the position of the query in the multi query
+	Index int
+	// Query is the query that failed
+	Query QueryInterface
+	// Err is the error of the query
+	Err error
+}
+
+// MultiQueryError is returned if some queries of a multi query failed; the items of the other queries are merged nevertheless
+type MultiQueryError struct {
+	// Failures are the failed queries in the order of the multi query
+	Failures []MultiQueryFailure
+}
+
+// Error returns the number of failed queries and the error of the first one
+func (e *MultiQueryError) Error() string {
+	return fmt.Sprintf("%d queries failed, first error: %v", len(e.Failures), e.Failures[0].Err)
+}
+
+// Unwrap returns the errors of the failed queries
+func (e *MultiQueryError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// multiQueryPart is a query of a multi query limited to the items the merged result can hold
+type multiQueryPart struct {
+	QueryInterface
+	limit *int64
+}
+
+// Limit returns the limit of the multi query if the query has no smaller limit
+func (q multiQueryPart) Limit() *int64 {
+	return q.limit
+}
+
+// Projection returns the projection of the query, the optional interfaces of the query are hidden by the embedding
+func (q multiQueryPart) Projection() []string {
+	return keyProjection(q.QueryInterface)
+}
+
+// ConsistentRead returns true if the query is read strongly consistent
+func (q multiQueryPart) ConsistentRead() bool {
+	return keyConsistentRead(q.QueryInterface)
+}
+
+// FilterExpression returns the filter expression of the query
+func (q multiQueryPart) FilterExpression() string {
+	filter, _ := queryFilter(q.QueryInterface)
+	return filter
+}
+
+// FilterArgs returns the arguments of the filter expression of the query
+func (q multiQueryPart) FilterArgs() []interface{} {
+	_, args := queryFilter(q.QueryInterface)
+	return args
+}
+
+// PageToken returns the page token of the query
+func (q multiQueryPart) PageToken() string {
+	return queryPageToken(q.QueryInterface)
+}
+
+// RangeValues returns the values the range key of the query is compared against
+func (q multiQueryPart) RangeValues() []interface{} {
+	return queryRangeValues(q.QueryInterface)
+}
+
+// SearchLimit returns the evaluation limit of the query
+func (q multiQueryPart) SearchLimit() *int64 {
+	return querySearchLimit(q.QueryInterface)
+}
+
+// runMultiQuery runs the queries of multiQuery with query, at most Concurrency at the same time, merges their items
+// in range key order and appends them to items
+// returns a MultiQueryError if some queries failed, items then holds the merged items of the other queries
+func runMultiQuery(ctx context.Context, multiQuery MultiQueryInterface, items any, query func(context.Context, QueryInterface, any) error) error {
+	if !IsPointerOFSlice(items) {
+		return ErrInvalidPointerSliceType
+	}
+
+	queries := multiQuery.Queries()
+	rangeKeyName, descending, err := multiQueryOrder(multiQuery)
+	if err != nil {
+		return err
+	}
+
+	results := make([][]map[string]*dynamodb.AttributeValue, len(queries))
+	errs := make([]error, len(queries))
+
+	concurrency := max(multiQuery.Concurrency(), 1)
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		// the queries that are not started yet when the context is done fail with its error
+		if errs[i] = ctx.Err(); errs[i] != nil {
+			continue
+		}
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			limit := q.Limit()
+			if globalLimit := multiQuery.Limit(); globalLimit != nil && (limit == nil || *globalLimit < *limit) {
+				limit = globalLimit
+			}
+			errs[i] = query(ctx, multiQueryPart{QueryInterface: q, limit: limit}, &results[i])
+		}()
+	}
+	wg.Wait()
+
+	var merged []map[string]*dynamodb.AttributeValue
+	multiErr := &MultiQueryError{}
+	for i, result := range results {
+		if errs[i] != nil {
+			multiErr.Failures = append(multiErr.Failures, MultiQueryFailure{Index: i, Query: queries[i], Err: errs[i]})
+			continue
+		}
+		merged = append(merged, result...)
+	}
+
+	if rangeKeyName != "" {
+		slices.SortStableFunc(merged, func(a, b map[string]*dynamodb.AttributeValue) int {
+			if descending {
+				return compareAttributeValues(b[rangeKeyName], a[rangeKeyName])
+			}
+			return compareAttributeValues(a[rangeKeyName], b[rangeKeyName])
+		})
+	}
+	if limit := valueFromPtr(multiQuery.Limit()); limit > 0 && int64(len(merged)) > limit {
+		merged = merged[:limit]
+	}
+
+	slice := reflect.ValueOf(items).Elem()
+	for _, item := range merged {
+		elem := reflect.New(slice.Type().Elem())
+		if err = dynamo.UnmarshalItem(item, elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+
+	if len(multiErr.Failures) > 0 {
+		return multiErr
+	}
+
+	return nil
+}
+
+// multiQueryOrder returns the range key name the items of multiQuery are merged by and if they are merged in descending order;
+// the range key name is empty if there is a single query, whose items are kept in their order
+// returns ErrInvalidMultiQuery if the queries have different orders or range key names, or if there are several queries
+// and neither they nor the multi query have a range key name
+func multiQueryOrder(multiQuery MultiQueryInterface) (string, bool, error) {
+	rangeKeyName := valueFromPtr(multiQuery.RangeKeyName())
+	queries := multiQuery.Queries()
+	for _, query := range queries {
+		if query.Descending() != queries[0].Descending() {
+			return "", false, ErrInvalidMultiQuery
+		}
+
+		name := valueFromPtr(query.RangeKeyName())
+		if name == "" || multiQuery.RangeKeyName() != nil {
+			continue
+		}
+		if rangeKeyName != "" && name != rangeKeyName {
+			return "", false, ErrInvalidMultiQuery
+		}
+		rangeKeyName = name
+	}
+	if rangeKeyName == "" && len(queries) > 1 {
+		return "", false, ErrInvalidMultiQuery
+	}
+
+	return rangeKeyName, len(queries) > 0 && queries[0].Descending(), nil
+}
+
+// compareAttributeValues compares range key values; numbers are compared by value, strings and binaries bytewise.
+// A missing value is less than any other value
+func compareAttributeValues(a, b *dynamodb.AttributeValue) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	case a.N != nil && b.N != nil:
+		x, _, errX := big.ParseFloat(*a.N, 10, 128, big.ToNearestEven)
+		y, _, errY := big.ParseFloat(*b.N, 10, 128, big.ToNearestEven)
+		if errX == nil && errY == nil {
+			return x.Cmp(y)
+		}
+		return strings.Compare(*a.N, *b.N)
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S)
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B)
+	}
+
+	return 0
+}
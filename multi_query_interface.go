@@ -0,0 +1,13 @@
+package djoemo
+
+// MultiQueryInterface provides an interface for djoemo multi queries used to run several queries and merge their items
+type MultiQueryInterface interface {
+	// Queries returns the queries in the order they were added
+	Queries() []QueryInterface
+	// RangeKeyName returns the name of the range key the items are merged by; nil if the range key name of the queries is used
+	RangeKeyName() *string
+	// Concurrency returns the number of queries that run at the same time
+	Concurrency() int
+	// Limit returns the maximum number of merged items
+	Limit() *int64
+}
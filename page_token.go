@@ -0,0 +1,199 @@
+package djoemo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+const (
+	// pageTokenScopeSize is the size of the scope digest a signed page token is bound to
+	pageTokenScopeSize = 16
+)
+
+// PageTokenScope identifies the query a page token is issued for
+type PageTokenScope struct {
+	TableName string
+	// IndexName is empty for queries on the table
+	IndexName string
+	HashKey   any
+}
+
+func newPageTokenScope(query QueryInterface, indexName string) PageTokenScope {
+	return PageTokenScope{
+		TableName: query.TableName(),
+		IndexName: indexName,
+		HashKey:   query.HashKey(),
+	}
+}
+
+// PageTokenMismatchError is returned if a page token was issued for a different table, index or hash key than the query it is used with
+type PageTokenMismatchError struct {
+	Scope PageTokenScope
+}
+
+// Error returns the error message
+func (e *PageTokenMismatchError) Error() string {
+	return "page token was issued for a different query on table " + e.Scope.TableName
+}
+
+// Is reports the mismatch as ErrInvalidPageToken, so both can be handled alike
+func (e *PageTokenMismatchError) Is(target error) bool {
+	return target == ErrInvalidPageToken
+}
+
+// plainPageTokenCodec encodes the last evaluated key as is; it is used if no codec is configured
+type plainPageTokenCodec struct{}
+
+// Encode encodes the last evaluated key into an opaque, URL-safe token
+func (plainPageTokenCodec) Encode(_ PageTokenScope, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (string, error) {
+	data, err := json.Marshal(lastEvaluatedKey)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode decodes a token created by Encode
+func (plainPageTokenCodec) Decode(_ PageTokenScope, token string) (map[string]*dynamodb.AttributeValue, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	return unmarshalPageKey(data)
+}
+
+// SignedPageTokenCodec signs page tokens with HMAC-SHA256 and binds them to the table, index and hash key of the query;
+// if an encryption key is set, the last evaluated key is encrypted with AES-GCM, so key values are not exposed
+type SignedPageTokenCodec struct {
+	signingKey []byte
+	aead       cipher.AEAD
+}
+
+// NewSignedPageTokenCodec factory method for signed page token codec; encryptionKey is optional and must be 16, 24 or 32 bytes long
+// returns ErrInvalidPageTokenKey if the signing key is empty or the encryption key has an invalid size
+func NewSignedPageTokenCodec(signingKey []byte, encryptionKey []byte) (*SignedPageTokenCodec, error) {
+	if len(signingKey) == 0 {
+		return nil, ErrInvalidPageTokenKey
+	}
+
+	codec := &SignedPageTokenCodec{signingKey: signingKey}
+	if len(encryptionKey) == 0 {
+		return codec, nil
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, ErrInvalidPageTokenKey
+	}
+	codec.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec, nil
+}
+
+// Encode encodes the last evaluated key into a signed, URL-safe token bound to scope
+func (c *SignedPageTokenCodec) Encode(scope PageTokenScope, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (string, error) {
+	digest, err := c.scopeDigest(scope)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(lastEvaluatedKey)
+	if err != nil {
+		return "", err
+	}
+
+	if c.aead != nil {
+		nonce := make([]byte, c.aead.NonceSize())
+		if _, err = rand.Read(nonce); err != nil {
+			return "", err
+		}
+		payload = c.aead.Seal(nonce, nonce, payload, digest)
+	}
+
+	token := append(append([]byte{}, digest...), payload...)
+	token = append(token, c.sign(token)...)
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Decode verifies a token created by Encode and decodes it into the key to resume from
+// returns ErrInvalidPageToken if the token is malformed or was tampered with, returns PageTokenMismatchError if it was issued for a different scope
+func (c *SignedPageTokenCodec) Decode(scope PageTokenScope, token string) (map[string]*dynamodb.AttributeValue, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) < pageTokenScopeSize+sha256.Size {
+		return nil, ErrInvalidPageToken
+	}
+
+	signed, signature := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if !hmac.Equal(signature, c.sign(signed)) {
+		return nil, ErrInvalidPageToken
+	}
+
+	digest, err := c.scopeDigest(scope)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signed[:pageTokenScopeSize], digest) {
+		return nil, &PageTokenMismatchError{Scope: scope}
+	}
+
+	payload := signed[pageTokenScopeSize:]
+	if c.aead != nil {
+		if len(payload) < c.aead.NonceSize() {
+			return nil, ErrInvalidPageToken
+		}
+		nonce, ciphertext := payload[:c.aead.NonceSize()], payload[c.aead.NonceSize():]
+		payload, err = c.aead.Open(nil, nonce, ciphertext, digest)
+		if err != nil {
+			return nil, ErrInvalidPageToken
+		}
+	}
+
+	return unmarshalPageKey(payload)
+}
+
+func (c *SignedPageTokenCodec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.signingKey)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// scopeDigest returns a keyed digest of scope, so the token does not reveal the hash key it is bound to
+func (c *SignedPageTokenCodec) scopeDigest(scope PageTokenScope) ([]byte, error) {
+	hashKey, err := dynamo.Marshal(scope.HashKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal([]any{scope.TableName, scope.IndexName, hashKey})
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, c.signingKey)
+	mac.Write([]byte("scope"))
+	mac.Write(data)
+	return mac.Sum(nil)[:pageTokenScopeSize], nil
+}
+
+func unmarshalPageKey(data []byte) (map[string]*dynamodb.AttributeValue, error) {
+	var key map[string]*dynamodb.AttributeValue
+	if err := json.Unmarshal(data, &key); err != nil || len(key) == 0 {
+		return nil, ErrInvalidPageToken
+	}
+
+	return key, nil
+}
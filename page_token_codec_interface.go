@@ -0,0 +1,16 @@
+package djoemo
+
+import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// PageTokenCodecInterface provides an interface to encode the last evaluated key of a query page into a page token and back
+//
+//go:generate mockgen -source=page_token_codec_interface.go -destination=./mock/page_token_codec_interface.go -package=mock .
+type PageTokenCodecInterface interface {
+	// Encode encodes the last evaluated key of a page of the query identified by scope into a URL-safe page token
+	Encode(scope PageTokenScope, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (string, error)
+	// Decode decodes a page token into the key to resume the query identified by scope from
+	// returns ErrInvalidPageToken if the token is malformed or was tampered with
+	Decode(scope PageTokenScope, token string) (map[string]*dynamodb.AttributeValue, error)
+}
@@ -0,0 +1,184 @@
+package djoemo_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Page Token", func() {
+	const UserTableName = "UserTable"
+
+	lastEvaluatedKey := map[string]*dynamodb.AttributeValue{
+		"UUID":     {S: aws.String("secret-uuid")},
+		"UserName": {S: aws.String("name")},
+	}
+	scope := djoemo.PageTokenScope{TableName: UserTableName, IndexName: "", HashKey: "secret-uuid"}
+
+	Describe("SignedPageTokenCodec", func() {
+		It("should reject invalid keys", func() {
+			_, err := djoemo.NewSignedPageTokenCodec(nil, nil)
+			Expect(err).To(Equal(djoemo.ErrInvalidPageTokenKey))
+
+			_, err = djoemo.NewSignedPageTokenCodec([]byte("signing"), []byte("short"))
+			Expect(err).To(Equal(djoemo.ErrInvalidPageTokenKey))
+		})
+
+		It("should encode and decode signed tokens", func() {
+			codec, err := djoemo.NewSignedPageTokenCodec([]byte("signing"), nil)
+			Expect(err).To(BeNil())
+
+			token, err := codec.Encode(scope, lastEvaluatedKey)
+			Expect(err).To(BeNil())
+
+			key, err := codec.Decode(scope, token)
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(lastEvaluatedKey))
+		})
+
+		It("should not expose key values of encrypted tokens", func() {
+			codec, err := djoemo.NewSignedPageTokenCodec([]byte("signing"), []byte("0123456789abcdef"))
+			Expect(err).To(BeNil())
+
+			token, err := codec.Encode(scope, lastEvaluatedKey)
+			Expect(err).To(BeNil())
+			data, err := base64.RawURLEncoding.DecodeString(token)
+			Expect(err).To(BeNil())
+			Expect(string(data)).NotTo(ContainSubstring("secret-uuid"))
+
+			key, err := codec.Decode(scope, token)
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(lastEvaluatedKey))
+		})
+
+		It("should reject tampered tokens", func() {
+			codec, _ := djoemo.NewSignedPageTokenCodec([]byte("signing"), nil)
+			token, _ := codec.Encode(scope, lastEvaluatedKey)
+
+			data, _ := base64.RawURLEncoding.DecodeString(token)
+			tampered := []byte(strings.Replace(string(data), "name", "eman", 1))
+
+			_, err := codec.Decode(scope, base64.RawURLEncoding.EncodeToString(tampered))
+			Expect(err).To(Equal(djoemo.ErrInvalidPageToken))
+
+			other, _ := djoemo.NewSignedPageTokenCodec([]byte("other"), nil)
+			_, err = other.Decode(scope, token)
+			Expect(err).To(Equal(djoemo.ErrInvalidPageToken))
+		})
+
+		It("should reject tokens issued for a different query", func() {
+			codec, _ := djoemo.NewSignedPageTokenCodec([]byte("signing"), []byte("0123456789abcdef"))
+			token, _ := codec.Encode(scope, lastEvaluatedKey)
+
+			for _, other := range []djoemo.PageTokenScope{
+				{TableName: "OtherTable", HashKey: "secret-uuid"},
+				{TableName: UserTableName, IndexName: "UserNameIndex", HashKey: "secret-uuid"},
+				{TableName: UserTableName, HashKey: "other-uuid"},
+			} {
+				_, err := codec.Decode(other, token)
+
+				var mismatchErr *djoemo.PageTokenMismatchError
+				Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+				Expect(mismatchErr.Scope).To(Equal(other))
+				Expect(errors.Is(err, djoemo.ErrInvalidPageToken)).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("Repository", func() {
+		var (
+			dMock       mock.DynamoMock
+			repository  djoemo.RepositoryInterface
+			metricsMock *mock.MockMetricsInterface
+		)
+
+		BeforeEach(func() {
+			mockCtrl := gomock.NewController(GinkgoT())
+			dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+			dMock = mock.NewDynamoMock(dAPIMock)
+			metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+			repository = djoemo.NewRepository(dAPIMock)
+			repository.WithMetrics(metricsMock)
+
+			codec, err := djoemo.NewSignedPageTokenCodec([]byte("signing"), []byte("0123456789abcdef"))
+			Expect(err).To(BeNil())
+			repository.WithPageTokenCodec(codec)
+		})
+
+		It("should issue tokens that only resume the same query", func() {
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithLimit(1)
+
+			dMock.Should().
+				Query(
+					dMock.WithTable(q.TableName()),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithLimit(1),
+					dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid", "UserName": "name1"}),
+					dMock.WithLastEvaluatedKey(map[string]interface{}{"UUID": "uuid", "UserName": "name1"}),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			var users []User
+			token, err := repository.QueryPageWithContext(context.Background(), q, &users)
+			Expect(err).To(BeNil())
+			Expect(token).NotTo(BeEmpty())
+
+			other := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("other").
+				WithPageToken(token)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, other, gomock.Any(), false)
+
+			_, err = repository.QueryPageWithContext(context.Background(), other, &users)
+			var mismatchErr *djoemo.PageTokenMismatchError
+			Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+
+			index := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithPageToken(token)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, index, gomock.Any(), false)
+
+			err = repository.GIndex("UserIndex").QueryWithContext(context.Background(), index, &users)
+			Expect(errors.As(err, &mismatchErr)).To(BeTrue())
+		})
+
+		It("should restore the plain codec if the codec is reset", func() {
+			Expect(repository.WithPageTokenCodec(nil)).To(BeIdenticalTo(repository))
+
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithLimit(1)
+
+			dMock.Should().
+				Query(
+					dMock.WithTable(q.TableName()),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithLimit(1),
+					dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid", "UserName": "name1"}),
+					dMock.WithLastEvaluatedKey(map[string]interface{}{"UUID": "uuid", "UserName": "name1"}),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			var users []User
+			token, err := repository.QueryPageWithContext(context.Background(), q, &users)
+			Expect(err).To(BeNil())
+			Expect(token).NotTo(BeEmpty())
+		})
+	})
+})
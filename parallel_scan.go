@@ -0,0 +1,203 @@
+package djoemo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/guregu/dynamo"
+)
+
+// ScanItem is an item read by a parallel scan
+type ScanItem struct {
+	// Segment is the segment the item was read from
+	Segment int
+	// Item is the item as it is stored
+	Item map[string]*dynamodb.AttributeValue
+}
+
+// UnmarshalItem unmarshals the item into out
+func (i ScanItem) UnmarshalItem(out any) error {
+	return dynamo.UnmarshalItem(i.Item, out)
+}
+
+// ScanSegmentProgress is the progress of a segment of a parallel scan; it is reported after every page of the segment
+// and once more when the segment is done
+type ScanSegmentProgress struct {
+	// Segment is the segment the progress belongs to
+	Segment int
+	// TotalSegments is the number of segments of the scan
+	TotalSegments int
+	// Pages is the number of pages read by the segment so far
+	Pages int
+	// Items is the number of items of the segment handled so far
+	Items int64
+	// Done is true if the segment is done, either because all its items are read or because it failed
+	Done bool
+	// Err is the error the segment failed with; it is only set if Done is true
+	Err error
+	// Checkpoint is the position of the segment after the items handled so far; a segment resumed from it
+	// with WithScanCheckpoint continues with the next page
+	Checkpoint ScanCheckpoint
+}
+
+// ParallelScanFailure is a segment of a parallel scan that failed
+type ParallelScanFailure struct {
+	// Segment is the segment that failed
+	Segment int
+	// Err is the error of the segment
+	Err error
+}
+
+// ParallelScanError is returned if some segments of a parallel scan failed; the other segments are scanned nevertheless
+type ParallelScanError struct {
+	// Failures are the failed segments in segment order
+	Failures []ParallelScanFailure
+}
+
+// Error returns the number of failed segments and the error of the first one
+func (e *ParallelScanError) Error() string {
+	return fmt.Sprintf("%d scan segments failed, first error: %v", len(e.Failures), e.Failures[0].Err)
+}
+
+// Unwrap returns the errors of the failed segments
+func (e *ParallelScanError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, failure := range e.Failures {
+		errs[i] = failure.Err
+	}
+	return errs
+}
+
+// parallelScan scans the segments of a table concurrently with the raw client, guregu/dynamo does not support segments
+type parallelScan struct {
+	client        dynamodbiface.DynamoDBAPI
+	input         dynamodb.ScanInput
+	totalSegments int
+	// checkpoints are the checkpoints the segments start from, by segment
+	checkpoints map[int]*ScanCheckpoint
+	options     scanOptions
+	handle      func(context.Context, ScanItem) error
+
+	// handled counts the items handled by all segments, it is compared to the result limit
+	handled atomic.Int64
+	// limitReached is set once the result limit is reached, the remaining segments are then cancelled
+	limitReached atomic.Bool
+
+	capacityMu sync.Mutex
+	cc         *dynamo.ConsumedCapacity
+}
+
+// run scans all segments and waits until they are done
+// returns a ParallelScanError if some segments failed
+func (s *parallelScan) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, s.totalSegments)
+	var wg sync.WaitGroup
+	for segment := range s.totalSegments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[segment] = s.scanSegment(ctx, cancel, segment)
+		}()
+	}
+	wg.Wait()
+
+	scanErr := &ParallelScanError{}
+	for segment, err := range errs {
+		if err != nil {
+			scanErr.Failures = append(scanErr.Failures, ParallelScanFailure{Segment: segment, Err: err})
+		}
+	}
+	if len(scanErr.Failures) > 0 {
+		return scanErr
+	}
+
+	return nil
+}
+
+// scanSegment reads the pages of segment and handles their items until the segment is done, the context is cancelled
+// or the result limit is reached
+func (s *parallelScan) scanSegment(ctx context.Context, cancel context.CancelFunc, segment int) (err error) {
+	pager := newScanPager(s.client, s.input, segment, s.totalSegments, s.checkpoints[segment], s.options.reads(ctx))
+	progress := ScanSegmentProgress{Segment: segment, TotalSegments: s.totalSegments}
+	pageDone := true
+	defer func() {
+		// segments cancelled because the result limit is reached did not fail
+		if s.limitReached.Load() && errors.Is(err, context.Canceled) {
+			err = nil
+		}
+		progress.Done = true
+		progress.Err = err
+		progress.Checkpoint = pager.checkpoint(pageDone)
+		s.report(progress)
+	}()
+
+	for !pager.done {
+		pageDone = false
+		output, err := pager.next(ctx)
+		if err != nil {
+			// the client reports cancellations as request errors, the context error tells the cause
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		s.addConsumedCapacity(output.ConsumedCapacity)
+		progress.Pages++
+
+		for _, item := range output.Items {
+			if s.options.limit > 0 && s.handled.Add(1) > s.options.limit {
+				s.limitReached.Store(true)
+				cancel()
+				return nil
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := s.handle(ctx, ScanItem{Segment: segment, Item: item}); err != nil {
+				return err
+			}
+			progress.Items++
+		}
+		pageDone = true
+
+		progress.Checkpoint = pager.checkpoint(true)
+		if err := s.saveCheckpoint(ctx, progress.Checkpoint); err != nil {
+			return err
+		}
+		if !pager.done {
+			s.report(progress)
+		}
+	}
+
+	return nil
+}
+
+func (s *parallelScan) report(progress ScanSegmentProgress) {
+	if s.options.progress != nil {
+		s.options.progress(progress)
+	}
+}
+
+// saveCheckpoint stores the checkpoint of a segment if the scan is resumable
+func (s *parallelScan) saveCheckpoint(ctx context.Context, checkpoint ScanCheckpoint) error {
+	if s.options.checkpointStore == nil {
+		return nil
+	}
+
+	return s.options.checkpointStore.Save(ctx, segmentCheckpointName(s.options.checkpointName, checkpoint.Segment, s.totalSegments), checkpoint)
+}
+
+// addConsumedCapacity adds the capacity of a page, the segments read pages concurrently
+func (s *parallelScan) addConsumedCapacity(capacity *dynamodb.ConsumedCapacity) {
+	s.capacityMu.Lock()
+	defer s.capacityMu.Unlock()
+	addConsumedCapacity(s.cc, capacity)
+}
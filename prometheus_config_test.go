@@ -47,6 +47,7 @@ var _ = Describe("Prometheus Config", func() {
 			dMock.Should().
 				Get(
 					dMock.WithTable(key.TableName()),
+					dMock.WithConsumedCapacity(),
 					dMock.WithHash(*key.HashKeyName(), key.HashKey()),
 					dMock.WithGetOutput(userDBOutput),
 				).Exec()
@@ -99,6 +100,7 @@ var _ = Describe("Prometheus Config", func() {
 			dMock.Should().
 				Get(
 					dMock.WithTable(key.TableName()),
+					dMock.WithConsumedCapacity(),
 					dMock.WithHash(*key.HashKeyName(), key.HashKey()),
 					dMock.WithGetOutput(nil),
 				).Exec()
@@ -153,6 +155,7 @@ var _ = Describe("Prometheus Config", func() {
 			dMock.Should().
 				Query(
 					dMock.WithTable(key.TableName()),
+					dMock.WithConsumedCapacity(),
 					dMock.WithIndex(IndexName),
 					dMock.WithCondition(*key.HashKeyName(), key.HashKey(), "EQ"),
 					dMock.WithQueryOutput(userDBOutput),
@@ -203,6 +206,7 @@ var _ = Describe("Prometheus Config", func() {
 			dMock.Should().
 				Query(
 					dMock.WithTable(key.TableName()),
+					dMock.WithConsumedCapacity(),
 					dMock.WithIndex(IndexName),
 					dMock.WithCondition(*key.HashKeyName(), key.HashKey(), "EQ"),
 					dMock.WithError(dynamoErr),
@@ -1,5 +1,7 @@
 package djoemo
 
+import "strconv"
+
 // Operator is an operation to apply comparisons.
 type Operator string
 
@@ -17,9 +19,14 @@ const (
 
 type query struct {
 	key
-	rangeOp    Operator
-	descending bool
-	limit      *int64
+	rangeOp     Operator
+	rangeValues []interface{}
+	descending  bool
+	limit       *int64
+	searchLimit *int64
+	filter      string
+	filterArgs  []interface{}
+	pageToken   string
 }
 
 // Key factory method to create struct that implements key interface
@@ -54,21 +61,74 @@ func (q *query) WithHashKey(hashKey interface{}) *query {
 // WithRangeKey set djoemo key range key value
 func (q *query) WithRangeKey(rangeKey interface{}) *query {
 	q.rangeKey = rangeKey
+	q.rangeValues = nil
+	return q
+}
+
+// WithRangeValues set djoemo query range key values for operators comparing against multiple values;
+// the range key is set to the first value, so keys built from the query still have a range key
+func (q *query) WithRangeValues(values ...interface{}) *query {
+	q.rangeKey = nil
+	if len(values) > 0 {
+		q.rangeKey = values[0]
+	}
+	q.rangeValues = values
 	return q
 }
 
+// WithRangeBetween set djoemo query range key condition to match range keys between lo and hi, inclusive
+func (q *query) WithRangeBetween(lo, hi interface{}) *query {
+	q.rangeOp = Between
+	return q.WithRangeValues(lo, hi)
+}
+
 // WithRangeKey set djoemo key range key value
 func (q *query) WithRangeOp(rangeOp Operator) *query {
 	q.rangeOp = rangeOp
 	return q
 }
 
-// WithLimit set djoemo query limit
+// WithProjection set djoemo query projection; only the given attributes are read and unmarshalled
+func (q *query) WithProjection(attributes ...string) *query {
+	q.projection = attributes
+	return q
+}
+
+// WithConsistentRead set djoemo query to be read strongly consistent
+func (q *query) WithConsistentRead() *query {
+	q.consistentRead = true
+	return q
+}
+
+// WithLimit set djoemo query result limit; at most limit items are returned, pages are read until the limit is reached
+// or all items are read, so with a filter expression more items may be evaluated. For QueryPageWithContext it is the page size
 func (q *query) WithLimit(limit int64) *query {
 	q.limit = &limit
 	return q
 }
 
+// WithSearchLimit set djoemo query evaluation limit; at most limit items are evaluated, before the filter expression is applied,
+// and the query stops after a single request, so it may return fewer items than the result limit even if more items match.
+// For QueryPageWithContext it takes precedence over the result limit as page size
+func (q *query) WithSearchLimit(limit int64) *query {
+	q.searchLimit = &limit
+	return q
+}
+
+// WithFilterExpression set djoemo query filter expression; it is applied server-side after the key conditions
+// and uses the same placeholders as ConditionalUpdateWithContext, ? for values and $ for attribute names
+func (q *query) WithFilterExpression(expression string, args ...interface{}) *query {
+	q.filter = expression
+	q.filterArgs = args
+	return q
+}
+
+// WithPageToken set djoemo query page token; the query resumes after the page the token was returned for
+func (q *query) WithPageToken(pageToken string) *query {
+	q.pageToken = pageToken
+	return q
+}
+
 // WithDescending set djoemo query desnding to true
 func (q *query) WithDescending() *query {
 	q.descending = true
@@ -83,12 +143,87 @@ func (q *query) RangeOp() Operator {
 	return q.rangeOp
 }
 
+// RangeValues returns the values the range key is compared against, empty if there is no range key condition
+func (q *query) RangeValues() []interface{} {
+	if len(q.rangeValues) > 0 {
+		return q.rangeValues
+	}
+	if q.rangeKey != nil {
+		return []interface{}{q.rangeKey}
+	}
+	return nil
+}
+
 // Limit returns the result limit
 func (q *query) Limit() *int64 {
 	return q.limit
 }
 
+// SearchLimit returns the evaluation limit
+func (q *query) SearchLimit() *int64 {
+	return q.searchLimit
+}
+
 // Descending returns scan direction
 func (q *query) Descending() bool {
 	return q.descending
 }
+
+// FilterExpression returns the filter expression, empty if the query is not filtered
+func (q *query) FilterExpression() string {
+	return q.filter
+}
+
+// FilterArgs returns the arguments of the filter expression placeholders
+func (q *query) FilterArgs() []interface{} {
+	return q.filterArgs
+}
+
+// PageToken returns the token of the page to resume from, empty if the query starts from the beginning
+func (q *query) PageToken() string {
+	return q.pageToken
+}
+
+// RangeConditionError is returned if the number of range key values does not match the operator of a query
+type RangeConditionError struct {
+	Operator Operator
+	Values   int
+}
+
+// Error returns the error message
+func (e *RangeConditionError) Error() string {
+	expected, supported := rangeOperands[e.Operator]
+	if !supported {
+		return "operator " + string(e.Operator) + " is not supported for range key conditions"
+	}
+	values := " range key values, got "
+	if expected == 1 {
+		values = " range key value, got "
+	}
+	return "operator " + string(e.Operator) + " expects " + strconv.Itoa(expected) + values + strconv.Itoa(e.Values)
+}
+
+// Is reports the error as ErrInvalidRangeCondition
+func (e *RangeConditionError) Is(target error) bool {
+	return target == ErrInvalidRangeCondition
+}
+
+// rangeOperands is the number of values each operator supported in range key conditions compares against
+var rangeOperands = map[Operator]int{
+	Equal:          1,
+	Less:           1,
+	LessOrEqual:    1,
+	Greater:        1,
+	GreaterOrEqual: 1,
+	BeginsWith:     1,
+	Between:        2,
+}
+
+// validateRangeCondition checks that the operator of query supports range key conditions and matches the number of range key values
+func validateRangeCondition(query QueryInterface) error {
+	values := len(queryRangeValues(query))
+	if expected, supported := rangeOperands[query.RangeOp()]; !supported || expected != values {
+		return &RangeConditionError{Operator: query.RangeOp(), Values: values}
+	}
+	return nil
+}
@@ -7,3 +7,65 @@ type QueryInterface interface {
 	Limit() *int64
 	Descending() bool
 }
+
+// FilterQueryInterface is implemented by queries that filter their items server-side
+type FilterQueryInterface interface {
+	// FilterExpression returns the filter expression, empty if the query is not filtered
+	FilterExpression() string
+	// FilterArgs returns the arguments of the filter expression placeholders
+	FilterArgs() []interface{}
+}
+
+// queryFilter returns the filter expression of query and its arguments, empty if query does not implement FilterQueryInterface
+func queryFilter(query QueryInterface) (string, []interface{}) {
+	if filterQuery, ok := query.(FilterQueryInterface); ok {
+		return filterQuery.FilterExpression(), filterQuery.FilterArgs()
+	}
+	return "", nil
+}
+
+// PageTokenQueryInterface is implemented by queries that resume from a page token
+type PageTokenQueryInterface interface {
+	// PageToken returns the token of the page to resume from, empty if the query starts from the beginning
+	PageToken() string
+}
+
+// queryPageToken returns the page token of query, empty if query does not implement PageTokenQueryInterface
+func queryPageToken(query QueryInterface) string {
+	if pageTokenQuery, ok := query.(PageTokenQueryInterface); ok {
+		return pageTokenQuery.PageToken()
+	}
+	return ""
+}
+
+// RangeValuesQueryInterface is implemented by queries that compare the range key against several values
+type RangeValuesQueryInterface interface {
+	// RangeValues returns the values the range key is compared against, empty if there is no range key condition
+	RangeValues() []interface{}
+}
+
+// queryRangeValues returns the values the range key of query is compared against, the range key if query does not implement
+// RangeValuesQueryInterface; empty if there is no range key condition
+func queryRangeValues(query QueryInterface) []interface{} {
+	if rangeQuery, ok := query.(RangeValuesQueryInterface); ok {
+		return rangeQuery.RangeValues()
+	}
+	if query.RangeKey() != nil {
+		return []interface{}{query.RangeKey()}
+	}
+	return nil
+}
+
+// SearchLimitQueryInterface is implemented by queries that limit the items evaluated
+type SearchLimitQueryInterface interface {
+	// SearchLimit returns the evaluation limit
+	SearchLimit() *int64
+}
+
+// querySearchLimit returns the evaluation limit of query, nil if query does not implement SearchLimitQueryInterface
+func querySearchLimit(query QueryInterface) *int64 {
+	if searchLimitQuery, ok := query.(SearchLimitQueryInterface); ok {
+		return searchLimitQuery.SearchLimit()
+	}
+	return nil
+}
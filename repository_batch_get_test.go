@@ -6,6 +6,7 @@ import (
 
 	"github.com/adjoeio/djoemo"
 	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	. "github.com/onsi/ginkgo/v2"
@@ -60,9 +61,8 @@ var _ = Describe("Repository BatchGetItemsWithContext", func() {
 		key2 := djoemo.Key().WithTableName("OtherTable").
 			WithHashKeyName("UUID").WithHashKey("uuid2")
 
-		// Note: success flag captured from outer err var which isn't
-		// updated for ErrInvalidBatchRequest return path.
-		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key1, gomock.Any(), false)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key2, gomock.Any(), false)
 
 		users := &[]User{}
 		found, err := repository.BatchGetItemsWithContext(
@@ -154,6 +154,148 @@ var _ = Describe("Repository BatchGetItemsWithContext", func() {
 		Expect(len(*profiles)).To(Equal(2))
 	})
 
+	It("should get projected attributes and retry unprocessed keys", func() {
+		key1 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid1").
+			WithProjection("UUID", "Meta.Name")
+		key2 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid2").
+			WithProjection("UUID", "Meta.Name")
+
+		item1, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid1"})
+		item2, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid2"})
+
+		gomock.InOrder(
+			dAPIMock.EXPECT().
+				BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+					request := input.RequestItems[UserTableName]
+					Expect(request.Keys).To(HaveLen(2))
+					Expect(*request.ProjectionExpression).To(Equal("#p0, #p1.#p2"))
+					Expect(*request.ExpressionAttributeNames["#p1"]).To(Equal("Meta"))
+					Expect(*request.ExpressionAttributeNames["#p2"]).To(Equal("Name"))
+
+					unprocessed := *request
+					unprocessed.Keys = request.Keys[1:]
+					return &dynamodb.BatchGetItemOutput{
+						Responses:       map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {item1}},
+						UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{UserTableName: &unprocessed},
+					}, nil
+				}),
+			dAPIMock.EXPECT().
+				BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+					Expect(input.RequestItems[UserTableName].Keys).To(HaveLen(1))
+					return &dynamodb.BatchGetItemOutput{
+						Responses: map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {item2}},
+					}, nil
+				}),
+		)
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key1, gomock.Any(), true)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key2, gomock.Any(), true)
+
+		users := &[]User{}
+		found, err := repository.BatchGetItemsWithContext(
+			context.Background(),
+			[]djoemo.KeyInterface{key1, key2},
+			users,
+		)
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+		Expect(*users).To(HaveLen(2))
+		Expect((*users)[1].UUID).To(Equal("uuid2"))
+	})
+
+	It("should return error when keys have different projections", func() {
+		key1 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid1").
+			WithProjection("UUID")
+		key2 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid2")
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key1, gomock.Any(), false)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key2, gomock.Any(), false)
+
+		users := &[]User{}
+		found, err := repository.BatchGetItemsWithContext(
+			context.Background(),
+			[]djoemo.KeyInterface{key1, key2},
+			users,
+		)
+		Expect(err).To(Equal(djoemo.ErrInvalidBatchProjection))
+		Expect(found).To(BeFalse())
+	})
+
+	It("should read the batch strongly consistent if any key is", func() {
+		key1 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid1")
+		key2 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid2").
+			WithConsistentRead()
+
+		item1, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid1"})
+		dAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				Expect(*input.RequestItems[UserTableName].ConsistentRead).To(BeTrue())
+				return &dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {item1}},
+				}, nil
+			})
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key1, gomock.Any(), true)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key2, gomock.Any(), true)
+
+		users := &[]User{}
+		found, err := repository.BatchGetItemsWithContext(
+			context.Background(),
+			[]djoemo.KeyInterface{key1, key2},
+			users,
+		)
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+	})
+
+	It("should return the unprocessed keys when dynamo does not process them after all retries", func() {
+		key1 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid1").
+			WithProjection("UUID")
+		key2 := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").WithHashKey("uuid2").
+			WithProjection("UUID")
+
+		item1, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid1"})
+		dAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				request := *input.RequestItems[UserTableName]
+				request.Keys = request.Keys[len(request.Keys)-1:]
+				return &dynamodb.BatchGetItemOutput{
+					Responses:       map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {item1}},
+					UnprocessedKeys: map[string]*dynamodb.KeysAndAttributes{UserTableName: &request},
+				}, nil
+			}).
+			Times(6)
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key1, gomock.Any(), false)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key2, gomock.Any(), false)
+
+		users := &[]User{}
+		found, err := repository.BatchGetItemsWithContext(
+			context.Background(),
+			[]djoemo.KeyInterface{key1, key2},
+			users,
+		)
+		Expect(found).To(BeFalse())
+		Expect(errors.Is(err, djoemo.ErrUnprocessedBatchRequest)).To(BeTrue())
+
+		var unprocessedErr *djoemo.UnprocessedKeysError
+		Expect(errors.As(err, &unprocessedErr)).To(BeTrue())
+		Expect(unprocessedErr.TableName).To(Equal(UserTableName))
+		Expect(unprocessedErr.Keys).To(Equal([]map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid2")}}}))
+	})
+
 	It("should return false and nil when dynamo returns ErrNotFound", func() {
 		key := djoemo.Key().WithTableName(UserTableName).
 			WithHashKeyName("UUID").WithHashKey("uuid")
@@ -0,0 +1,347 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capacity Limiter", func() {
+	const UserTableName = "UserTable"
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	capacity := func(units float64) *dynamodb.ConsumedCapacity {
+		return &dynamodb.ConsumedCapacity{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(units)}
+	}
+	throttled := awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "throughput exceeded", nil)
+
+	Describe("Scan Iterator", func() {
+		key := djoemo.Key().WithTableName(UserTableName)
+
+		It("should wait for the read capacity consumed by the pages", func() {
+			// every page consumes half of the capacity per second, so the fourth page waits half a second
+			pages := 0
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					Expect(input.ReturnConsumedCapacity).To(Equal(aws.String(dynamodb.ReturnConsumedCapacityTotal)))
+					pages++
+					output := &dynamodb.ScanOutput{
+						Items:            []map[string]*dynamodb.AttributeValue{userItem(fmt.Sprintf("uuid%d", pages))},
+						ConsumedCapacity: capacity(50),
+					}
+					if pages < 4 {
+						output.LastEvaluatedKey = userItem(fmt.Sprintf("uuid%d", pages))
+					}
+					return output, nil
+				}).Times(4)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			limiter := djoemo.NewCapacityLimiter(100, 0)
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 1, djoemo.WithScanCapacityLimiter(limiter))
+			Expect(err).To(BeNil())
+
+			start := time.Now()
+			var users []User
+			var user User
+			for itr.NextItem(&user) {
+				users = append(users, user)
+			}
+			Expect(users).To(HaveLen(4))
+			Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+			Expect(limiter.ReadUnitsPerSecond()).To(Equal(100.0))
+		})
+
+		It("should slow down and retry a page that is throttled", func() {
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(nil, throttled),
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid1")}, ConsumedCapacity: capacity(1)}, nil),
+			)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			limiter := djoemo.NewCapacityLimiter(1000, 0)
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 1, djoemo.WithScanCapacityLimiter(limiter))
+			Expect(err).To(BeNil())
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(user.UUID).To(Equal("uuid1"))
+			Expect(itr.NextItem(&user)).To(BeFalse())
+			// the rate is halved and recovers over time, not with the page that succeeded
+			rate := limiter.ReadUnitsPerSecond()
+			Expect(rate).To(BeNumerically(">=", 500))
+			Expect(rate).To(BeNumerically("<", 550))
+
+			// a tenth of the configured rate is recovered per second
+			time.Sleep(200 * time.Millisecond)
+			Expect(limiter.ReadUnitsPerSecond()).To(BeNumerically(">=", rate+20))
+		})
+
+		It("should limit scans by the limiter of the context", func() {
+			// the first page consumes ten seconds of budget, so the second one is not read before the context is done
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					Expect(input.ReturnConsumedCapacity).To(Equal(aws.String(dynamodb.ReturnConsumedCapacityTotal)))
+					return &dynamodb.ScanOutput{
+						Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1")},
+						LastEvaluatedKey: userItem("uuid1"),
+						ConsumedCapacity: capacity(11),
+					}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			ctx, cancel := context.WithTimeout(djoemo.WithCapacityLimiter(context.Background(), djoemo.NewCapacityLimiter(1, 0)), 50*time.Millisecond)
+			defer cancel()
+			itr, err := repository.ScanIteratorWithContext(ctx, key, 1)
+			Expect(err).To(BeNil())
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(itr.NextItem(&user)).To(BeFalse())
+			Expect(errors.Is(itr.Err(), context.DeadlineExceeded)).To(BeTrue())
+		})
+
+		It("should limit scans by the limiter of the scan instead of the one of the context", func() {
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{
+					Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1")},
+					LastEvaluatedKey: userItem("uuid1"),
+					ConsumedCapacity: capacity(11),
+				}, nil)
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid2")}, ConsumedCapacity: capacity(1)}, nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			ctx, cancel := context.WithTimeout(djoemo.WithCapacityLimiter(context.Background(), djoemo.NewCapacityLimiter(1, 0)), time.Second)
+			defer cancel()
+			itr, err := repository.ScanIteratorWithContext(ctx, key, 1, djoemo.WithScanCapacityLimiter(djoemo.NewCapacityLimiter(1000, 0)))
+			Expect(err).To(BeNil())
+
+			var users []User
+			var user User
+			for itr.NextItem(&user) {
+				users = append(users, user)
+			}
+			Expect(itr.Err()).To(BeNil())
+			Expect(users).To(HaveLen(2))
+		})
+
+		It("should not limit scans without limiter", func() {
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					Expect(input.ReturnConsumedCapacity).To(BeNil())
+					return &dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid1")}}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 1)
+			Expect(err).To(BeNil())
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(itr.NextItem(&user)).To(BeFalse())
+		})
+	})
+
+	Describe("Parallel Scan", func() {
+		key := djoemo.Key().WithTableName(UserTableName)
+
+		It("should stop waiting for capacity when the context is done", func() {
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{
+					Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1")},
+					LastEvaluatedKey: userItem("uuid1"),
+					ConsumedCapacity: capacity(1000),
+				}, nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := repository.ParallelScanWithContext(ctx, key, 1, func(context.Context, djoemo.ScanItem) error {
+				return nil
+			}, djoemo.WithScanCapacityLimiter(djoemo.NewCapacityLimiter(10, 0)))
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+	})
+
+	Describe("Scan Count", func() {
+		key := djoemo.Key().WithTableName(UserTableName)
+
+		It("should wait for the read capacity consumed by the pages and retry a page that is throttled", func() {
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+						Expect(input.ReturnConsumedCapacity).To(Equal(aws.String(dynamodb.ReturnConsumedCapacityTotal)))
+						return &dynamodb.ScanOutput{Count: aws.Int64(2), LastEvaluatedKey: userItem("uuid2"), ConsumedCapacity: capacity(150)}, nil
+					}),
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(nil, throttled),
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ScanOutput{Count: aws.Int64(1), ConsumedCapacity: capacity(1)}, nil),
+			)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			limiter := djoemo.NewCapacityLimiter(100, 0)
+			start := time.Now()
+			count, err := repository.ScanCountWithContext(djoemo.WithCapacityLimiter(context.Background(), limiter), key, "")
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(3))
+			// the second page waits for the half second of capacity consumed beyond the budget of the first
+			Expect(time.Since(start)).To(BeNumerically(">=", 500*time.Millisecond))
+			Expect(limiter.ReadUnitsPerSecond()).To(BeNumerically("<", 100))
+		})
+	})
+
+	Describe("Batch Get", func() {
+		It("should request the consumed capacity and retry unprocessed keys", func() {
+			key1 := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid1")
+			key2 := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid2")
+
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+						Expect(input.ReturnConsumedCapacity).To(Equal(aws.String(dynamodb.ReturnConsumedCapacityTotal)))
+						Expect(input.RequestItems[UserTableName].Keys).To(HaveLen(2))
+						return &dynamodb.BatchGetItemOutput{
+							Responses:        map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {userItem("uuid1")}},
+							UnprocessedKeys:  map[string]*dynamodb.KeysAndAttributes{UserTableName: {Keys: []map[string]*dynamodb.AttributeValue{userItem("uuid2")}}},
+							ConsumedCapacity: []*dynamodb.ConsumedCapacity{capacity(1)},
+						}, nil
+					}),
+				dMock.DynamoDBAPIMock.EXPECT().
+					BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+						Expect(input.RequestItems[UserTableName].Keys).To(Equal([]map[string]*dynamodb.AttributeValue{userItem("uuid2")}))
+						return &dynamodb.BatchGetItemOutput{
+							Responses:        map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {userItem("uuid2")}},
+							ConsumedCapacity: []*dynamodb.ConsumedCapacity{capacity(1)},
+						}, nil
+					}),
+			)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key1, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key2, gomock.Any(), true)
+
+			limiter := djoemo.NewCapacityLimiter(100, 0)
+			users := &[]User{}
+			found, err := repository.BatchGetItemsWithContext(djoemo.WithCapacityLimiter(context.Background(), limiter), []djoemo.KeyInterface{key1, key2}, users)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeTrue())
+			Expect(*users).To(HaveLen(2))
+			// the unprocessed keys slow the limiter down
+			Expect(limiter.ReadUnitsPerSecond()).To(BeNumerically("<", 100))
+		})
+	})
+
+	Describe("Batch Write", func() {
+		key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+		It("should save items in chunks and retry unprocessed items", func() {
+			users := make([]User, 30)
+			for i := range users {
+				users[i] = User{UUID: fmt.Sprintf("uuid%d", i)}
+			}
+
+			var written int
+			dMock.DynamoDBAPIMock.EXPECT().
+				BatchWriteItemWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.BatchWriteItemInput, _ ...any) (*dynamodb.BatchWriteItemOutput, error) {
+					Expect(input.ReturnConsumedCapacity).To(Equal(aws.String(dynamodb.ReturnConsumedCapacityTotal)))
+					requests := input.RequestItems[UserTableName]
+					Expect(len(requests)).To(BeNumerically("<=", 25))
+					output := &dynamodb.BatchWriteItemOutput{ConsumedCapacity: []*dynamodb.ConsumedCapacity{capacity(float64(len(requests)))}}
+					// the first request of 25 items leaves the last item unprocessed
+					if len(requests) == 25 {
+						output.UnprocessedItems = map[string][]*dynamodb.WriteRequest{UserTableName: requests[24:]}
+						requests = requests[:24]
+					}
+					for _, request := range requests {
+						Expect(request.PutRequest).NotTo(BeNil())
+						written++
+					}
+					return output, nil
+				}).Times(3)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpCommit, key, gomock.Any(), true)
+
+			limiter := djoemo.NewCapacityLimiter(0, 1000)
+			err := repository.SaveItemsWithContext(djoemo.WithCapacityLimiter(context.Background(), limiter), key, users)
+			Expect(err).To(BeNil())
+			Expect(written).To(Equal(30))
+			Expect(limiter.WriteUnitsPerSecond()).To(BeNumerically("<", 1000))
+			Expect(limiter.ReadUnitsPerSecond()).To(Equal(0.0))
+		})
+
+		It("should delete items by key", func() {
+			key1 := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid1")
+			key2 := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid2")
+
+			dMock.DynamoDBAPIMock.EXPECT().
+				BatchWriteItemWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.BatchWriteItemInput, _ ...any) (*dynamodb.BatchWriteItemOutput, error) {
+					requests := input.RequestItems[UserTableName]
+					Expect(requests).To(HaveLen(2))
+					Expect(requests[0].DeleteRequest.Key).To(Equal(userItem("uuid1")))
+					Expect(requests[1].DeleteRequest.Key).To(Equal(userItem("uuid2")))
+					return &dynamodb.BatchWriteItemOutput{ConsumedCapacity: []*dynamodb.ConsumedCapacity{capacity(2)}}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpDelete, key1, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpDelete, key2, gomock.Any(), true)
+
+			ctx := djoemo.WithCapacityLimiter(context.Background(), djoemo.NewCapacityLimiter(0, 100))
+			err := repository.DeleteItemsWithContext(ctx, []djoemo.KeyInterface{key1, key2})
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail when writes stay throttled", func() {
+			dMock.DynamoDBAPIMock.EXPECT().
+				BatchWriteItemWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, throttled).
+				Times(2)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpCommit, key, gomock.Any(), false)
+
+			ctx, cancel := context.WithTimeout(djoemo.WithCapacityLimiter(context.Background(), djoemo.NewCapacityLimiter(0, 100)), 150*time.Millisecond)
+			defer cancel()
+
+			err := repository.SaveItemsWithContext(ctx, key, []User{{UUID: "uuid1"}})
+			Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		})
+	})
+})
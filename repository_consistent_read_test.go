@@ -0,0 +1,137 @@
+package djoemo_test
+
+import (
+	"context"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Repository Consistent Read", func() {
+	const UserTableName = "UserTable"
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	It("should get item consistent if set on key", func() {
+		key := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").
+			WithHashKey("uuid").
+			WithConsistentRead()
+
+		dMock.Should().
+			Get(
+				dMock.WithTable(key.TableName()),
+				dMock.WithHash(*key.HashKeyName(), key.HashKey()),
+				dMock.WithConsistentRead(),
+				dMock.WithGetOutput(map[string]interface{}{"UUID": "uuid"}),
+			).Exec()
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+		user := &User{}
+		found, err := repository.GetItemWithContext(context.Background(), key, user)
+		Expect(err).To(BeNil())
+		Expect(found).To(BeTrue())
+	})
+
+	It("should query consistent if set on context", func() {
+		q := djoemo.Query().WithTableName(UserTableName).
+			WithHashKeyName("UUID").
+			WithHashKey("uuid")
+
+		dMock.Should().
+			Query(
+				dMock.WithTable(q.TableName()),
+				dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+				dMock.WithConsistentRead(),
+				dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid"}),
+			).Exec()
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+		var users []User
+		err := repository.QueryWithContext(djoemo.WithConsistentRead(context.Background(), true), q, &users)
+		Expect(err).To(BeNil())
+		Expect(users).To(HaveLen(1))
+	})
+
+	It("should read tables consistent by default unless disabled on context", func() {
+		repository.WithConsistentReadTables(UserTableName)
+		key := djoemo.Key().WithTableName(UserTableName).
+			WithHashKeyName("UUID").
+			WithHashKey("uuid")
+
+		var consistent []bool
+		dMock.DynamoDBAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				consistent = append(consistent, *input.RequestItems[UserTableName].ConsistentRead)
+				return &dynamodb.BatchGetItemOutput{}, nil
+			}).Times(2)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true).Times(2)
+
+		users := &[]User{}
+		_, err := repository.BatchGetItemsWithContext(context.Background(), []djoemo.KeyInterface{key}, users)
+		Expect(err).To(BeNil())
+		_, err = repository.BatchGetItemsWithContext(djoemo.WithConsistentRead(context.Background(), false), []djoemo.KeyInterface{key}, users)
+		Expect(err).To(BeNil())
+
+		Expect(consistent).To(Equal([]bool{true, false}))
+	})
+
+	Describe("GlobalIndex", func() {
+		It("should reject consistent reads set on key", func() {
+			key := djoemo.Key().WithTableName(UserTableName).
+				WithHashKeyName("UserName").
+				WithHashKey("name").
+				WithConsistentRead()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			user := &User{}
+			found, err := repository.GIndex("UserNameIndex").GetItemWithContext(context.Background(), key, user)
+			Expect(err).To(Equal(djoemo.ErrConsistentReadNotSupported))
+			Expect(found).To(BeFalse())
+		})
+
+		It("should reject consistent reads set on context but ignore table defaults", func() {
+			repository.WithConsistentReadTables(UserTableName)
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UserName").
+				WithHashKey("name")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+			var users []User
+			err := repository.GIndex("UserNameIndex").QueryWithContext(djoemo.WithConsistentRead(context.Background(), true), q, &users)
+			Expect(err).To(Equal(djoemo.ErrConsistentReadNotSupported))
+
+			dMock.Should().
+				Query(
+					dMock.WithIndex("UserNameIndex"),
+					dMock.WithTable(q.TableName()),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid"}),
+				).Exec()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			err = repository.GIndex("UserNameIndex").QueryWithContext(context.Background(), q, &users)
+			Expect(err).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,256 @@
+package djoemo_test
+
+import (
+	"context"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// capacityMetrics is a metrics publisher that also records consumed capacity
+type capacityMetrics struct {
+	*mock.MockMetricsInterface
+	*mock.MockCapacityMetricsInterface
+}
+
+var _ = Describe("Consumed Capacity", func() {
+	const (
+		UserTableName = "UserTable"
+		IndexName     = "UserNameIndex"
+	)
+
+	var (
+		dMock         mock.DynamoMock
+		repository    djoemo.RepositoryInterface
+		metricsMock   *mock.MockMetricsInterface
+		capacityMock  *mock.MockCapacityMetricsInterface
+		consumedTable = &dynamodb.ConsumedCapacity{
+			TableName:     aws.String(UserTableName),
+			CapacityUnits: aws.Float64(3),
+			Table:         &dynamodb.Capacity{CapacityUnits: aws.Float64(1)},
+			GlobalSecondaryIndexes: map[string]*dynamodb.Capacity{
+				IndexName: {CapacityUnits: aws.Float64(2)},
+			},
+		}
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		capacityMock = mock.NewMockCapacityMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(capacityMetrics{metricsMock, capacityMock})
+	})
+
+	It("should record the capacity consumed by a save", func() {
+		key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			PutItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.PutItemInput, _ ...any) (*dynamodb.PutItemOutput, error) {
+				Expect(*input.ReturnConsumedCapacity).To(Equal(dynamodb.ReturnConsumedCapacityIndexes))
+				return &dynamodb.PutItemOutput{ConsumedCapacity: consumedTable}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpCommit, key, gomock.Any(), true)
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpCommit, key, djoemo.ConsumedCapacity{
+			TableName: UserTableName,
+			Total:     3,
+			Table:     1,
+			Indexes:   map[string]float64{IndexName: 2},
+		})
+
+		err := repository.SaveItemWithContext(context.Background(), key, User{UUID: "uuid"})
+		Expect(err).To(BeNil())
+	})
+
+	It("should record the capacity consumed by a query of a global index", func() {
+		q := djoemo.Query().WithTableName(UserTableName).WithHashKeyName("UserName").WithHashKey("name")
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				Expect(*input.IndexName).To(Equal(IndexName))
+				Expect(*input.ReturnConsumedCapacity).To(Equal(dynamodb.ReturnConsumedCapacityIndexes))
+				return &dynamodb.QueryOutput{
+					Count: aws.Int64(0),
+					ConsumedCapacity: &dynamodb.ConsumedCapacity{
+						TableName:              aws.String(UserTableName),
+						CapacityUnits:          aws.Float64(0.5),
+						GlobalSecondaryIndexes: map[string]*dynamodb.Capacity{IndexName: {CapacityUnits: aws.Float64(0.5)}},
+					},
+				}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, q, djoemo.ConsumedCapacity{
+			TableName: UserTableName,
+			Total:     0.5,
+			Indexes:   map[string]float64{IndexName: 0.5},
+		})
+
+		var users []User
+		err := repository.GIndex(IndexName).QueryWithContext(context.Background(), q, &users)
+		Expect(err).To(BeNil())
+	})
+
+	It("should record the capacity of all pages of a scan count", func() {
+		key := djoemo.Key().WithTableName(UserTableName)
+
+		lastEvaluatedKey := map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}}
+		gomock.InOrder(
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					Expect(*input.ReturnConsumedCapacity).To(Equal(dynamodb.ReturnConsumedCapacityIndexes))
+					return &dynamodb.ScanOutput{
+						Count:            aws.Int64(2),
+						LastEvaluatedKey: lastEvaluatedKey,
+						ConsumedCapacity: &dynamodb.ConsumedCapacity{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(1)},
+					}, nil
+				}),
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{
+					Count:            aws.Int64(1),
+					ConsumedCapacity: &dynamodb.ConsumedCapacity{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(0.5)},
+				}, nil),
+		)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, key, djoemo.ConsumedCapacity{TableName: UserTableName, Total: 1.5})
+
+		count, err := repository.ScanCountWithContext(context.Background(), key, "")
+		Expect(err).To(BeNil())
+		Expect(count).To(BeEquivalentTo(3))
+	})
+
+	It("should record the capacity of a query iterator when a page is read", func() {
+		q := djoemo.Query().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			Return(&dynamodb.QueryOutput{
+				Count:            aws.Int64(1),
+				Items:            []map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid")}}},
+				ConsumedCapacity: &dynamodb.ConsumedCapacity{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(0.5)},
+			}, nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+		itr, err := repository.QueryIteratorWithContext(context.Background(), q)
+		Expect(err).To(BeNil())
+
+		// the capacity is recorded with the page, so it is recorded even if the iteration is abandoned
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, q, djoemo.ConsumedCapacity{TableName: UserTableName, Total: 0.5})
+		var user User
+		Expect(itr.NextItem(&user)).To(BeTrue())
+
+		Expect(itr.NextItem(&user)).To(BeFalse())
+		Expect(itr.NextItem(&user)).To(BeFalse())
+	})
+
+	It("should record the capacity of every page of a scan iterator", func() {
+		key := djoemo.Key().WithTableName(UserTableName)
+
+		gomock.InOrder(
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{
+					Items:            []map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid1")}}},
+					LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid1")}},
+					ConsumedCapacity: &dynamodb.ConsumedCapacity{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(1)},
+				}, nil),
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{
+					Items:            []map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid2")}}},
+					LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid2")}},
+					ConsumedCapacity: &dynamodb.ConsumedCapacity{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(0.5)},
+				}, nil),
+		)
+
+		itr, err := repository.ScanIteratorWithContext(context.Background(), key, 0)
+		Expect(err).To(BeNil())
+
+		var user User
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, key, djoemo.ConsumedCapacity{TableName: UserTableName, Total: 1})
+		Expect(itr.NextItem(&user)).To(BeTrue())
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, key, djoemo.ConsumedCapacity{TableName: UserTableName, Total: 0.5})
+		Expect(itr.NextItem(&user)).To(BeTrue())
+		// the iteration is abandoned with more pages to read, so the scan is not recorded but the capacity of its pages is
+	})
+
+	It("should record the capacity consumed by a write transaction for every table", func() {
+		user := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+		profile := djoemo.Key().WithTableName("ProfileTable").WithHashKeyName("UUID").WithHashKey("uuid")
+		otherProfile := djoemo.Key().WithTableName("ProfileTable").WithHashKeyName("UUID").WithHashKey("uuid2")
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...any) (*dynamodb.TransactWriteItemsOutput, error) {
+				Expect(*input.ReturnConsumedCapacity).To(Equal(dynamodb.ReturnConsumedCapacityIndexes))
+				return &dynamodb.TransactWriteItemsOutput{ConsumedCapacity: []*dynamodb.ConsumedCapacity{
+					{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(2)},
+					{TableName: aws.String("ProfileTable"), CapacityUnits: aws.Float64(4)},
+				}}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, gomock.Any(), gomock.Any(), true).Times(3)
+		// the capacity of a table is recorded once, for the first key of the table
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpTransaction, user, djoemo.ConsumedCapacity{TableName: UserTableName, Total: 2})
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpTransaction, profile, djoemo.ConsumedCapacity{TableName: "ProfileTable", Total: 4})
+
+		tx := djoemo.TransactWrite().
+			Put(user, &User{UUID: "uuid"}).
+			Put(profile, &Profile{UUID: "uuid"}).
+			Delete(otherProfile)
+		err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+		Expect(err).To(BeNil())
+	})
+
+	It("should record the capacity consumed by a read transaction for every table", func() {
+		user := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+		profile := djoemo.Key().WithTableName("ProfileTable").WithHashKeyName("UUID").WithHashKey("uuid")
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			TransactGetItemsWithContext(gomock.Any(), gomock.Any()).
+			Return(&dynamodb.TransactGetItemsOutput{
+				Responses: []*dynamodb.ItemResponse{
+					{Item: map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}}},
+					{Item: map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}}},
+				},
+				ConsumedCapacity: []*dynamodb.ConsumedCapacity{
+					{TableName: aws.String(UserTableName), CapacityUnits: aws.Float64(1)},
+					{TableName: aws.String("ProfileTable"), CapacityUnits: aws.Float64(0.5)},
+				},
+			}, nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(2)
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, user, djoemo.ConsumedCapacity{TableName: UserTableName, Total: 1})
+		capacityMock.EXPECT().RecordConsumedCapacity(gomock.Any(), djoemo.OpRead, profile, djoemo.ConsumedCapacity{TableName: "ProfileTable", Total: 0.5})
+
+		missing, err := repository.TransactGetItemsWithContext(context.Background(), djoemo.TransactGet().Get(user, &User{}).Get(profile, &Profile{}))
+		Expect(err).To(BeNil())
+		Expect(missing).To(BeEmpty())
+	})
+
+	It("should not request the consumed capacity without a publisher recording it", func() {
+		repository := djoemo.NewRepository(dMock.DynamoDBAPIMock)
+		repository.WithMetrics(metricsMock)
+		key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			PutItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.PutItemInput, _ ...any) (*dynamodb.PutItemOutput, error) {
+				Expect(input.ReturnConsumedCapacity).To(BeNil())
+				return &dynamodb.PutItemOutput{}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpCommit, key, gomock.Any(), true)
+
+		err := repository.SaveItemWithContext(context.Background(), key, User{UUID: "uuid"})
+		Expect(err).To(BeNil())
+	})
+})
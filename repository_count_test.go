@@ -0,0 +1,210 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Count", func() {
+	const (
+		UserTableName = "UserTable"
+		IndexName     = "UserNameIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	Describe("CountWithContext", func() {
+		It("should fail with invalid key", func() {
+			q := djoemo.Query().WithTableName(UserTableName).WithHashKey("uuid")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+			count, err := repository.CountWithContext(context.Background(), q)
+			Expect(err).To(Equal(djoemo.ErrInvalidHashKeyName))
+			Expect(count).To(BeZero())
+		})
+
+		It("should count the items of a filtered query", func() {
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithFilterExpression("UserName = ?", "name")
+
+			dMock.Should().
+				Query(
+					dMock.WithTable(q.TableName()),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithFilterExpression("(UserName = ?)", "name"),
+					dMock.WithCount(),
+					dMock.WithCountOutput(3),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			count, err := repository.CountWithContext(context.Background(), q)
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(3))
+		})
+
+		It("should count all pages and ignore limit and projection", func() {
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithLimit(1).
+				WithProjection("UserName")
+			lastEvaluatedKey := map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}}
+
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+						Expect(*input.Select).To(Equal(dynamodb.SelectCount))
+						Expect(input.Limit).To(BeNil())
+						Expect(input.ProjectionExpression).To(BeNil())
+						return &dynamodb.QueryOutput{Count: aws.Int64(2), LastEvaluatedKey: lastEvaluatedKey}, nil
+					}),
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+						Expect(input.ExclusiveStartKey).To(Equal(lastEvaluatedKey))
+						return &dynamodb.QueryOutput{Count: aws.Int64(5)}, nil
+					}),
+			)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			count, err := repository.CountWithContext(context.Background(), q)
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(7))
+		})
+
+		It("should count the items of a global index query", func() {
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UserName").
+				WithHashKey("name")
+
+			dMock.Should().
+				Query(
+					dMock.WithTable(q.TableName()),
+					dMock.WithIndex(IndexName),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithCount(),
+					dMock.WithCountOutput(4),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			count, err := repository.GIndex(IndexName).CountWithContext(context.Background(), q)
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(4))
+		})
+
+		It("should return error of the query", func() {
+			q := djoemo.Query().WithTableName(UserTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid")
+			dbErr := errors.New("some dynamo error")
+
+			dMock.DynamoDBAPIMock.EXPECT().QueryWithContext(gomock.Any(), gomock.Any()).Return(nil, dbErr)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+			count, err := repository.CountWithContext(context.Background(), q)
+			Expect(err).To(Equal(dbErr))
+			Expect(count).To(BeZero())
+		})
+	})
+
+	Describe("ScanCountWithContext", func() {
+		It("should fail with invalid table name", func() {
+			key := djoemo.Key()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			count, err := repository.ScanCountWithContext(context.Background(), key, "")
+			Expect(err).To(Equal(djoemo.ErrInvalidTableName))
+			Expect(count).To(BeZero())
+		})
+
+		It("should count the items of the table", func() {
+			key := djoemo.Key().WithTableName(UserTableName)
+
+			dMock.Should().
+				ScanAll(
+					dMock.WithTable(UserTableName),
+					dMock.WithFilterExpression("(UserName = ?)", "name"),
+					dMock.WithCount(),
+					dMock.WithCountOutput(10),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			count, err := repository.ScanCountWithContext(context.Background(), key, "UserName = ?", "name")
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(10))
+		})
+
+		It("should count all pages with attribute name placeholders", func() {
+			key := djoemo.Key().WithTableName(UserTableName)
+			lastEvaluatedKey := map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}}
+
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+						Expect(*input.Select).To(Equal(dynamodb.SelectCount))
+						Expect(*input.FilterExpression).To(Equal("(#sKN2GC5DVOM = :v0 AND #sKNUXUZI > :v1)"))
+						Expect(input.ExpressionAttributeNames).To(Equal(map[string]*string{
+							"#sKN2GC5DVOM": aws.String("Status"),
+							"#sKNUXUZI":    aws.String("Size"),
+						}))
+						Expect(input.ExpressionAttributeValues).To(Equal(map[string]*dynamodb.AttributeValue{
+							":v0": {S: aws.String("active")},
+							":v1": {N: aws.String("2")},
+						}))
+						return &dynamodb.ScanOutput{Count: aws.Int64(6), LastEvaluatedKey: lastEvaluatedKey}, nil
+					}),
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+						Expect(input.ExclusiveStartKey).To(Equal(lastEvaluatedKey))
+						return &dynamodb.ScanOutput{Count: aws.Int64(1)}, nil
+					}),
+			)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			count, err := repository.ScanCountWithContext(context.Background(), key, "'Status' = ? AND $ > ?", "active", "Size", 2)
+			Expect(err).To(BeNil())
+			Expect(count).To(BeEquivalentTo(7))
+		})
+
+		It("should fail if the args do not match the filter", func() {
+			key := djoemo.Key().WithTableName(UserTableName)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			count, err := repository.ScanCountWithContext(context.Background(), key, "UserName = ? AND TraceID = ?", "name")
+			Expect(err).To(Equal(djoemo.ErrInvalidExpressionArgs))
+			Expect(count).To(BeZero())
+		})
+	})
+})
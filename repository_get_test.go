@@ -6,6 +6,8 @@ import (
 
 	"github.com/adjoeio/djoemo"
 	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/guregu/dynamo"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -135,6 +137,35 @@ var _ = Describe("Repository", func() {
 				Expect(user.UUID).To(Equal(userDBOutput["UUID"]))
 			})
 
+			It("should get item with projection", func() {
+				key := djoemo.Key().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithProjection("UserName", "TraceID")
+
+				userDBOutput := map[string]interface{}{
+					"UUID":     "uuid",
+					"UserName": "name",
+				}
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+				dMock.Should().
+					Get(
+						dMock.WithTable(key.TableName()),
+						dMock.WithHash(*key.HashKeyName(), key.HashKey()),
+						dMock.WithProjection("UserName", "TraceID"),
+						dMock.WithGetOutput(userDBOutput),
+					).Exec()
+
+				user := &User{}
+				found, err := repository.GetItemWithContext(context.Background(), key, user)
+
+				Expect(err).To(BeNil())
+				Expect(found).To(BeTrue())
+				Expect(user.UserName).To(Equal("name"))
+			})
+
 			It("should get item with Hash and range", func() {
 				key := djoemo.Key().WithTableName(ProfileTableName).
 					WithHashKeyName("UUID").
@@ -407,6 +438,150 @@ var _ = Describe("Repository", func() {
 				Expect(users[1].UserName).To(Equal("userTwo"))
 			})
 		})
+		Describe("GetItems with Iterator and result limit", func() {
+			It("should stop after the result limit while reading pages of the search limit", func() {
+				key := djoemo.Key().WithTableName(UserTableName)
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+				dMock.Should().ScanAll(
+					dMock.WithTable(UserTableName),
+					dMock.WithScanAllOutput([]map[string]interface{}{
+						{"UUID": "uuid1", "UserName": "user1"},
+						{"UUID": "uuid2", "UserName": "user2"},
+						{"UUID": "uuid3", "UserName": "user3"},
+					}),
+					dMock.WithSearchLimit(10),
+				).Exec()
+
+				itr, err := repository.ScanIteratorWithContext(context.Background(), key, 10, djoemo.WithScanLimit(2))
+				Expect(err).To(BeNil())
+
+				user := User{}
+				var users []User
+				for itr.NextItem(&user) {
+					users = append(users, user)
+				}
+
+				Expect(users).To(HaveLen(2))
+				Expect(users[1].UserName).To(Equal("user2"))
+			})
+		})
+		Describe("GetItems with Iterator, filter and projection", func() {
+			It("should read the projection of the key and filter the items", func() {
+				key := djoemo.Key().WithTableName(UserTableName).WithProjection("UUID", "UserName")
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+						Expect(*input.TableName).To(Equal(UserTableName))
+						Expect(*input.ProjectionExpression).To(Equal("#p0, #p1"))
+						Expect(*input.FilterExpression).To(Equal("(#sKRZGCY3FJFCA = :v0)"))
+						Expect(input.ExpressionAttributeNames).To(Equal(map[string]*string{
+							"#p0":            aws.String("UUID"),
+							"#p1":            aws.String("UserName"),
+							"#sKRZGCY3FJFCA": aws.String("TraceID"),
+						}))
+						Expect(input.ExpressionAttributeValues).To(ContainElement(&dynamodb.AttributeValue{S: aws.String("trace")}))
+						return &dynamodb.ScanOutput{
+							Items: []map[string]*dynamodb.AttributeValue{
+								{"UUID": {S: aws.String("uuid")}, "UserName": {S: aws.String("user")}},
+							},
+						}, nil
+					})
+
+				itr, err := repository.ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanFilter("$ = ?", "TraceID", "trace"))
+				Expect(err).To(BeNil())
+
+				user := User{}
+				var users []User
+				for itr.NextItem(&user) {
+					users = append(users, user)
+				}
+
+				Expect(users).To(Equal([]User{{UUID: "uuid", UserName: "user"}}))
+			})
+
+			It("should return error if the args do not match the filter", func() {
+				key := djoemo.Key().WithTableName(UserTableName)
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+				itr, err := repository.ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanFilter("$ = ?", "TraceID"))
+				Expect(err).To(Equal(djoemo.ErrInvalidExpressionArgs))
+				Expect(itr).To(BeNil())
+			})
+		})
+		Describe("GetItems with Iterator and errors", func() {
+			key := djoemo.Key().WithTableName(UserTableName)
+
+			It("should stop and return the error of a page", func() {
+				dbErr := errors.New("some dynamo error")
+				gomock.InOrder(
+					dMock.DynamoDBAPIMock.EXPECT().
+						ScanWithContext(gomock.Any(), gomock.Any()).
+						Return(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid1")}, LastEvaluatedKey: userItem("uuid1")}, nil),
+					dMock.DynamoDBAPIMock.EXPECT().
+						ScanWithContext(gomock.Any(), gomock.Any()).
+						Return(nil, dbErr),
+				)
+				// the iteration that ends in an error is recorded as failure
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+				itr, err := repository.ScanIteratorWithContext(context.Background(), key, 1)
+				Expect(err).To(BeNil())
+
+				user := User{}
+				Expect(itr.NextItem(&user)).To(BeTrue())
+				Expect(itr.Err()).To(BeNil())
+				Expect(itr.NextItem(&user)).To(BeFalse())
+				Expect(itr.Err()).To(Equal(dbErr))
+				Expect(itr.NextItem(&user)).To(BeFalse())
+			})
+
+			It("should stop when the context is cancelled", func() {
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ScanOutput{
+						Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1"), userItem("uuid2")},
+						LastEvaluatedKey: userItem("uuid2"),
+					}, nil)
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				itr, err := repository.ScanIteratorWithContext(ctx, key, 2)
+				Expect(err).To(BeNil())
+
+				user := User{}
+				Expect(itr.NextItem(&user)).To(BeTrue())
+				cancel()
+				// the items of the page that was read are not returned anymore and no further page is read
+				Expect(itr.NextItem(&user)).To(BeFalse())
+				Expect(itr.Err()).To(Equal(context.Canceled))
+			})
+
+			It("should not fail at the end of the items", func() {
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid1")}}, nil)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				itr, err := repository.ScanIteratorWithContext(ctx, key, 0)
+				Expect(err).To(BeNil())
+
+				user := User{}
+				Expect(itr.NextItem(&user)).To(BeTrue())
+				// the scan is recorded once, when the iteration ends
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+				Expect(itr.NextItem(&user)).To(BeFalse())
+				// a context cancelled after the iteration ended does not fail it
+				cancel()
+				Expect(itr.NextItem(&user)).To(BeFalse())
+				Expect(itr.Err()).To(BeNil())
+			})
+		})
 		Describe("Log", func() {
 			It("should log with extra fields if log is supported for GetItemWithContext", func() {
 				key := djoemo.Key().WithTableName(UserTableName).
@@ -8,6 +8,8 @@ import (
 
 	"github.com/adjoeio/djoemo"
 	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -457,6 +459,59 @@ var _ = Describe("Global Index", func() {
 				Expect(profiles[1].UUID).To(Equal("uuid2"))
 			})
 
+			It("should query items with projection", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithProjection("UserName", "TraceID")
+
+				userDBOutput := map[string]interface{}{
+					"UUID":     "uuid",
+					"UserName": "name",
+				}
+
+				dMock.Should().
+					Query(
+						dMock.WithIndex(IndexName),
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithProjection("UserName", "TraceID"),
+						dMock.WithQueryOutput(userDBOutput),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				err := repository.GIndex(IndexName).QueryWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users[0].UserName).To(Equal("name"))
+			})
+
+			It("should query a page", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(1)
+
+				dMock.Should().
+					Query(
+						dMock.WithIndex(IndexName),
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithLimit(1),
+						dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid"}),
+						dMock.WithLastEvaluatedKey(map[string]interface{}{"UUID": "uuid"}),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				token, err := repository.GIndex(IndexName).QueryPageWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+				Expect(token).NotTo(BeEmpty())
+			})
+
 			It("should query items with limit and order", func() {
 				q := djoemo.Query().WithTableName(UserTableName).
 					WithHashKeyName("UUID").
@@ -486,6 +541,37 @@ var _ = Describe("Global Index", func() {
 				Expect(users[0].UUID).To(Equal(userDBOutput["UUID"]))
 			})
 
+			It("should query items with filter expression", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(2).
+					WithFilterExpression("UserName = ? AND TraceID <> ?", "name", "trace")
+
+				userDBOutput := map[string]interface{}{
+					"UUID":     "uuid",
+					"UserName": "name",
+				}
+
+				dMock.Should().
+					Query(
+						dMock.WithIndex(IndexName),
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithFilterExpression("(UserName = ? AND TraceID <> ?)", "name", "trace"),
+						dMock.WithQueryOutput(userDBOutput),
+						dMock.WithLimit(2),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				err := repository.GIndex(IndexName).QueryWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+				Expect(users[0].UserName).To(Equal("name"))
+			})
+
 			It("should return error if output is not pointer to slice ", func() {
 				q := djoemo.Query().WithTableName(ProfileTableName).
 					WithHashKeyName("UUID").
@@ -502,4 +588,45 @@ var _ = Describe("Global Index", func() {
 			})
 		})
 	})
+
+	Describe("Scan Iterator", func() {
+		It("should scan the index with filter and projection", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithProjection("UUID")
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					Expect(*input.TableName).To(Equal(UserTableName))
+					Expect(*input.IndexName).To(Equal(IndexName))
+					Expect(*input.Limit).To(BeEquivalentTo(5))
+					Expect(input.ProjectionExpression).NotTo(BeNil())
+					Expect(input.FilterExpression).NotTo(BeNil())
+					return &dynamodb.ScanOutput{
+						Items: []map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid")}}},
+					}, nil
+				})
+
+			itr, err := repository.GIndex(IndexName).ScanIteratorWithContext(context.Background(), key, 5, djoemo.WithScanFilter("attribute_exists($)", "TraceID"))
+			Expect(err).To(BeNil())
+
+			user := User{}
+			var users []User
+			for itr.NextItem(&user) {
+				users = append(users, user)
+			}
+
+			Expect(users).To(Equal([]User{{UUID: "uuid"}}))
+		})
+
+		It("should return error for consistent reads", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithConsistentRead()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			_, err := repository.GIndex(IndexName).ScanIteratorWithContext(context.Background(), key, 0)
+			Expect(err).To(Equal(djoemo.ErrConsistentReadNotSupported))
+		})
+	})
 })
@@ -0,0 +1,216 @@
+package djoemo_test
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hydrated Global Index Query", func() {
+	const (
+		UserTableName = "UserTable"
+		IndexName     = "UserNameIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	userTable := djoemo.TableSpec{TableName: UserTableName, HashKeyName: "UUID"}
+	nameQuery := djoemo.Query().WithTableName(UserTableName).WithHashKeyName("UserName").WithHashKey("name")
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	// expectIndexKeys answers the index query with the keys of the given users
+	expectIndexKeys := func(uuids ...string) {
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				Expect(*input.IndexName).To(Equal(IndexName))
+				Expect(input.ExpressionAttributeNames).To(ContainElement(aws.String("UUID")))
+				output := &dynamodb.QueryOutput{Count: aws.Int64(int64(len(uuids)))}
+				for _, uuid := range uuids {
+					output.Items = append(output.Items, map[string]*dynamodb.AttributeValue{
+						"UUID":     {S: aws.String(uuid)},
+						"UserName": {S: aws.String("name")},
+					})
+				}
+				return output, nil
+			})
+	}
+
+	// tableItem returns the table item of the user
+	tableItem := func(uuid string) map[string]*dynamodb.AttributeValue {
+		return userItem(uuid, "UserName", "name", "TraceID", uuid+"-trace")
+	}
+
+	It("should return the items of the table in the order of the index", func() {
+		expectIndexKeys("uuid3", "uuid1", "uuid2")
+		dMock.DynamoDBAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				Expect(input.RequestItems[UserTableName].Keys).To(Equal([]map[string]*dynamodb.AttributeValue{
+					{"UUID": {S: aws.String("uuid3")}},
+					{"UUID": {S: aws.String("uuid1")}},
+					{"UUID": {S: aws.String("uuid2")}},
+				}))
+				// uuid2 was deleted after the index was queried
+				return &dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{
+						UserTableName: {tableItem("uuid1"), tableItem("uuid3")},
+					},
+				}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, nameQuery, gomock.Any(), true)
+
+		var users []User
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(context.Background(), nameQuery, userTable, &users)
+		Expect(err).To(BeNil())
+		Expect(users).To(Equal([]User{
+			{UUID: "uuid3", UserName: "name", TraceID: "uuid3-trace"},
+			{UUID: "uuid1", UserName: "name", TraceID: "uuid1-trace"},
+		}))
+	})
+
+	It("should batch get the items in chunks", func() {
+		uuids := make([]string, 150)
+		for i := range uuids {
+			uuids[i] = "uuid" + strconv.Itoa(i)
+		}
+		expectIndexKeys(uuids...)
+
+		var requested []int
+		dMock.DynamoDBAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				keys := input.RequestItems[UserTableName].Keys
+				requested = append(requested, len(keys))
+				output := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]*dynamodb.AttributeValue{}}
+				for i := len(keys) - 1; i >= 0; i-- {
+					output.Responses[UserTableName] = append(output.Responses[UserTableName], tableItem(*keys[i]["UUID"].S))
+				}
+				return output, nil
+			}).Times(2)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, nameQuery, gomock.Any(), true)
+
+		var users []User
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(context.Background(), nameQuery, userTable, &users)
+		Expect(err).To(BeNil())
+		Expect(requested).To(Equal([]int{100, 50}))
+		Expect(users).To(HaveLen(150))
+		for i, user := range users {
+			Expect(user.UUID).To(Equal(uuids[i]))
+		}
+	})
+
+	It("should project the key attributes with the projection of the query", func() {
+		q := djoemo.Query().WithTableName(UserTableName).WithHashKeyName("UserName").WithHashKey("name").WithProjection("TraceID")
+
+		expectIndexKeys("uuid1")
+		dMock.DynamoDBAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				request := input.RequestItems[UserTableName]
+				Expect(*request.ProjectionExpression).To(Equal("#p0, #p1"))
+				Expect(request.ExpressionAttributeNames).To(Equal(map[string]*string{
+					"#p0": aws.String("TraceID"),
+					"#p1": aws.String("UUID"),
+				}))
+				return &dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{
+						UserTableName: {{"UUID": {S: aws.String("uuid1")}, "TraceID": {S: aws.String("uuid1-trace")}}},
+					},
+				}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+		var users []User
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(context.Background(), q, userTable, &users)
+		Expect(err).To(BeNil())
+		Expect(users).To(Equal([]User{{UUID: "uuid1", TraceID: "uuid1-trace"}}))
+	})
+
+	It("should read the items of the table strongly consistent if requested", func() {
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				// global secondary indexes do not support strongly consistent reads
+				Expect(aws.BoolValue(input.ConsistentRead)).To(BeFalse())
+				return &dynamodb.QueryOutput{
+					Count: aws.Int64(1),
+					Items: []map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid1")}, "UserName": {S: aws.String("name")}}},
+				}, nil
+			})
+		dMock.DynamoDBAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				Expect(aws.BoolValue(input.RequestItems[UserTableName].ConsistentRead)).To(BeTrue())
+				return &dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {tableItem("uuid1")}},
+				}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, nameQuery, gomock.Any(), true)
+
+		var users []User
+		ctx := djoemo.WithConsistentRead(context.Background(), true)
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(ctx, nameQuery, userTable, &users)
+		Expect(err).To(BeNil())
+		Expect(users).To(Equal([]User{{UUID: "uuid1", UserName: "name", TraceID: "uuid1-trace"}}))
+	})
+
+	It("should read the items of the table strongly consistent by default of the table", func() {
+		expectIndexKeys("uuid1")
+		dMock.DynamoDBAPIMock.EXPECT().
+			BatchGetItemWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.BatchGetItemInput, _ ...any) (*dynamodb.BatchGetItemOutput, error) {
+				Expect(aws.BoolValue(input.RequestItems[UserTableName].ConsistentRead)).To(BeTrue())
+				return &dynamodb.BatchGetItemOutput{
+					Responses: map[string][]map[string]*dynamodb.AttributeValue{UserTableName: {tableItem("uuid1")}},
+				}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, nameQuery, gomock.Any(), true)
+
+		var users []User
+		repository.WithConsistentReadTables(UserTableName)
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(context.Background(), nameQuery, userTable, &users)
+		Expect(err).To(BeNil())
+		Expect(users).To(HaveLen(1))
+	})
+
+	It("should fail if the items of the index miss the keys of the table", func() {
+		expectIndexKeys("uuid1")
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, nameQuery, gomock.Any(), false)
+
+		profileTable := djoemo.TableSpec{TableName: UserTableName, HashKeyName: "UUID", RangeKeyName: "CreatedAt"}
+		var users []User
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(context.Background(), nameQuery, profileTable, &users)
+		Expect(err).To(Equal(djoemo.ErrMissingTableKey))
+	})
+
+	It("should fail if the table is not the table of the query", func() {
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, nameQuery, gomock.Any(), false)
+
+		otherTable := djoemo.TableSpec{TableName: "OtherTable", HashKeyName: "UUID"}
+		var users []User
+		err := repository.GIndex(IndexName).QueryHydratedWithContext(context.Background(), nameQuery, otherTable, &users)
+		Expect(err).To(Equal(djoemo.ErrInvalidTableName))
+		Expect(users).To(BeEmpty())
+	})
+})
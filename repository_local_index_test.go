@@ -0,0 +1,183 @@
+package djoemo_test
+
+import (
+	"context"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Local Index", func() {
+	const (
+		ProfileTableName = "ProfileTable"
+		IndexName        = "UpdatedAtIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		logMock     *mock.MockLogInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		logMock = mock.NewMockLogInterface(mockCtrl)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithLog(logMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	Describe("GetItem", func() {
+		It("should return error if key is invalid", func() {
+			key := djoemo.Key().WithHashKeyName("UUID").WithHashKey("uuid")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			profile := &Profile{}
+			found, err := repository.LIndex(IndexName).GetItemWithContext(context.Background(), key, profile)
+			Expect(err).To(Equal(djoemo.ErrInvalidTableName))
+			Expect(found).To(BeFalse())
+		})
+
+		It("should get item with consistent read", func() {
+			key := djoemo.Key().WithTableName(ProfileTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithConsistentRead()
+
+			dMock.Should().
+				Query(
+					dMock.WithIndex(IndexName),
+					dMock.WithTable(key.TableName()),
+					dMock.WithCondition(*key.HashKeyName(), key.HashKey(), string(djoemo.Equal)),
+					dMock.WithConsistentRead(),
+					dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid", "Email": "user@adjoe.io"}),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			profile := &Profile{}
+			found, err := repository.LIndex(IndexName).GetItemWithContext(context.Background(), key, profile)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeTrue())
+			Expect(profile.Email).To(Equal("user@adjoe.io"))
+		})
+
+		It("should return false and nil if item was not found", func() {
+			key := djoemo.Key().WithTableName(ProfileTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid")
+
+			dMock.Should().
+				Query(
+					dMock.WithIndex(IndexName),
+					dMock.WithTable(key.TableName()),
+					dMock.WithCondition(*key.HashKeyName(), key.HashKey(), string(djoemo.Equal)),
+					dMock.WithQueryOutput(nil),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().WithField(djoemo.TableName, key.TableName()).Return(logMock)
+			logMock.EXPECT().Info(djoemo.ErrNoItemFound.Error())
+
+			profile := &Profile{}
+			found, err := repository.LIndex(IndexName).GetItemWithContext(context.Background(), key, profile)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("Query", func() {
+		It("should query items consistent by table default", func() {
+			repository.WithConsistentReadTables(ProfileTableName)
+			q := djoemo.Query().WithTableName(ProfileTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid").
+				WithRangeKeyName("UpdatedAt").
+				WithRangeKey(10).
+				WithRangeOp(djoemo.Greater).
+				WithDescending()
+
+			dMock.Should().
+				Query(
+					dMock.WithIndex(IndexName),
+					dMock.WithTable(q.TableName()),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithCondition(*q.RangeKeyName(), q.RangeKey(), string(djoemo.Greater)),
+					dMock.WithConsistentRead(),
+					dMock.WithDesc(true),
+					dMock.WithQueryOutput([]map[string]interface{}{{"UUID": "uuid1"}, {"UUID": "uuid2"}}),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			var profiles []Profile
+			err := repository.LIndex(IndexName).QueryWithContext(context.Background(), q, &profiles)
+			Expect(err).To(BeNil())
+			Expect(profiles).To(HaveLen(2))
+		})
+
+		It("should iterate items", func() {
+			q := djoemo.Query().WithTableName(ProfileTableName).
+				WithHashKeyName("UUID").
+				WithHashKey("uuid")
+
+			dMock.Should().
+				Query(
+					dMock.WithIndex(IndexName),
+					dMock.WithTable(q.TableName()),
+					dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+					dMock.WithQueryOutput([]map[string]interface{}{{"UUID": "uuid1"}, {"UUID": "uuid2"}}),
+				).Exec()
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			itr, err := repository.LIndex(IndexName).QueryIteratorWithContext(context.Background(), q)
+			Expect(err).To(BeNil())
+
+			count := 0
+			profile := Profile{}
+			for itr.NextItem(&profile) {
+				count++
+			}
+			Expect(itr.Err()).To(BeNil())
+			Expect(count).To(Equal(2))
+		})
+	})
+
+	Describe("Metrics", func() {
+		It("should label metrics with the index name", func() {
+			registry := prometheus.NewRegistry()
+			repository.WithPrometheusMetrics(registry, nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), false).Times(2)
+
+			ctx := djoemo.WithSourceLabel(context.Background(), "profile-service")
+			key := djoemo.Key().WithHashKeyName("UUID").WithHashKey("uuid")
+			_, _ = repository.LIndex(IndexName).GetItemWithContext(ctx, key, &Profile{})
+			_, _ = repository.GetItemWithContext(ctx, key, &Profile{})
+
+			mfs, err := registry.Gather()
+			Expect(err).To(BeNil())
+
+			var indexes []string
+			for _, mf := range mfs {
+				if mf.GetName() != "adjoe_djoemo_read" {
+					continue
+				}
+				for _, metric := range mf.GetMetric() {
+					Expect(getLabelValue(metric.GetLabel(), "source")).To(Equal("profile-service"))
+					indexes = append(indexes, getLabelValue(metric.GetLabel(), "index"))
+				}
+			}
+			Expect(indexes).To(ConsistOf(IndexName, ""))
+		})
+	})
+})
@@ -0,0 +1,308 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Event model with app id as hash key and timestamp as range key
+type Event struct {
+	AppID     string
+	Timestamp int64
+}
+
+var _ = Describe("Multi Query", func() {
+	const (
+		EventTableName = "EventTable"
+		IndexName      = "AppIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	eventQuery := func(appID string, descending bool) djoemo.QueryInterface {
+		q := djoemo.Query().WithTableName(EventTableName).
+			WithHashKeyName("AppID").
+			WithHashKey(appID)
+		if descending {
+			q = q.WithRangeKeyName("Timestamp").WithRangeKey(0).WithRangeOp(djoemo.Greater).WithDescending()
+		}
+		return q
+	}
+
+	// expectEvents answers every query with the events of the queried app, the timestamps of an app are returned in the given order
+	expectEvents := func(events map[string][]int64, check func(input *dynamodb.QueryInput)) {
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				if check != nil {
+					check(input)
+				}
+				appID := *input.KeyConditions["AppID"].AttributeValueList[0].S
+				output := &dynamodb.QueryOutput{}
+				for _, timestamp := range events[appID] {
+					output.Items = append(output.Items, map[string]*dynamodb.AttributeValue{
+						"AppID":     {S: aws.String(appID)},
+						"Timestamp": {N: aws.String(strconv.FormatInt(timestamp, 10))},
+					})
+				}
+				output.Count = aws.Int64(int64(len(output.Items)))
+				return output, nil
+			}).AnyTimes()
+	}
+
+	It("should merge the items of all queries in range key order", func() {
+		expectEvents(map[string][]int64{
+			"app1": {1, 5, 30},
+			"app2": {2, 4},
+			"app3": {3, 100},
+		}, func(input *dynamodb.QueryInput) {
+			Expect(*input.Limit).To(BeEquivalentTo(4))
+		})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(3)
+
+		multiQuery := djoemo.MultiQuery(eventQuery("app1", false), eventQuery("app2", false), eventQuery("app3", false)).
+			WithRangeKeyName("Timestamp").
+			WithLimit(4)
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(BeNil())
+		Expect(events).To(Equal([]Event{
+			{AppID: "app1", Timestamp: 1},
+			{AppID: "app2", Timestamp: 2},
+			{AppID: "app3", Timestamp: 3},
+			{AppID: "app2", Timestamp: 4},
+		}))
+	})
+
+	It("should keep the projection and the consistent read of the queries", func() {
+		var mu sync.Mutex
+		var inputs []*dynamodb.QueryInput
+		expectEvents(map[string][]int64{"app1": {1}, "app2": {2}}, func(input *dynamodb.QueryInput) {
+			mu.Lock()
+			defer mu.Unlock()
+			inputs = append(inputs, input)
+		})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(2)
+
+		projectedQuery := func(appID string) djoemo.QueryInterface {
+			return djoemo.Query().WithTableName(EventTableName).
+				WithHashKeyName("AppID").
+				WithHashKey(appID).
+				WithProjection("AppID", "Timestamp").
+				WithConsistentRead()
+		}
+		multiQuery := djoemo.MultiQuery(projectedQuery("app1"), projectedQuery("app2")).WithRangeKeyName("Timestamp")
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(BeNil())
+		Expect(events).To(Equal([]Event{{AppID: "app1", Timestamp: 1}, {AppID: "app2", Timestamp: 2}}))
+		Expect(inputs).To(HaveLen(2))
+		for _, input := range inputs {
+			Expect(input.ProjectionExpression).NotTo(BeNil())
+			// guregu/dynamo only substitutes the names that need a placeholder
+			var projected []string
+			for _, name := range strings.Split(*input.ProjectionExpression, ", ") {
+				if placeholder, ok := input.ExpressionAttributeNames[name]; ok {
+					name = *placeholder
+				}
+				projected = append(projected, name)
+			}
+			Expect(projected).To(Equal([]string{"AppID", "Timestamp"}))
+			Expect(aws.BoolValue(input.ConsistentRead)).To(BeTrue())
+		}
+	})
+
+	It("should keep the filter of the queries", func() {
+		var mu sync.Mutex
+		var inputs []*dynamodb.QueryInput
+		expectEvents(map[string][]int64{"app1": {1}, "app2": {2}}, func(input *dynamodb.QueryInput) {
+			mu.Lock()
+			defer mu.Unlock()
+			inputs = append(inputs, input)
+		})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(2)
+
+		filteredQuery := func(appID string) djoemo.QueryInterface {
+			return djoemo.Query().WithTableName(EventTableName).
+				WithHashKeyName("AppID").
+				WithHashKey(appID).
+				WithFilterExpression("Timestamp > ?", 0)
+		}
+		multiQuery := djoemo.MultiQuery(filteredQuery("app1"), filteredQuery("app2")).WithRangeKeyName("Timestamp")
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(BeNil())
+		Expect(inputs).To(HaveLen(2))
+		for _, input := range inputs {
+			Expect(aws.StringValue(input.FilterExpression)).To(Equal("(Timestamp > :v0)"))
+			Expect(input.ExpressionAttributeValues).To(HaveKeyWithValue(":v0", &dynamodb.AttributeValue{N: aws.String("0")}))
+		}
+	})
+
+	It("should merge in descending order of the queries", func() {
+		expectEvents(map[string][]int64{
+			"app1": {30, 5},
+			"app2": {10, 2},
+		}, func(input *dynamodb.QueryInput) {
+			Expect(*input.ScanIndexForward).To(BeFalse())
+		})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(2)
+
+		multiQuery := djoemo.MultiQuery().
+			WithQuery(eventQuery("app1", true)).
+			WithQuery(eventQuery("app2", true))
+
+		var events []Event
+		err := repository.GIndex(IndexName).MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(BeNil())
+		Expect(events).To(Equal([]Event{
+			{AppID: "app1", Timestamp: 30},
+			{AppID: "app2", Timestamp: 10},
+			{AppID: "app1", Timestamp: 5},
+			{AppID: "app2", Timestamp: 2},
+		}))
+	})
+
+	It("should report failed queries and return the items of the others", func() {
+		dbErr := errors.New("some dynamo error")
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				appID := *input.KeyConditions["AppID"].AttributeValueList[0].S
+				if appID == "app2" {
+					return nil, dbErr
+				}
+				return &dynamodb.QueryOutput{
+					Items: []map[string]*dynamodb.AttributeValue{{
+						"AppID":     {S: aws.String(appID)},
+						"Timestamp": {N: aws.String("1")},
+					}},
+					Count: aws.Int64(1),
+				}, nil
+			}).Times(3)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(2)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), false)
+
+		app2Query := eventQuery("app2", false)
+		multiQuery := djoemo.MultiQuery(eventQuery("app1", false), app2Query, eventQuery("app3", false)).WithRangeKeyName("Timestamp")
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+
+		var multiErr *djoemo.MultiQueryError
+		Expect(errors.As(err, &multiErr)).To(BeTrue())
+		Expect(errors.Is(err, dbErr)).To(BeTrue())
+		Expect(multiErr.Failures).To(HaveLen(1))
+		Expect(multiErr.Failures[0].Index).To(Equal(1))
+		Expect(multiErr.Failures[0].Query).To(Equal(app2Query))
+		Expect(events).To(Equal([]Event{
+			{AppID: "app1", Timestamp: 1},
+			{AppID: "app3", Timestamp: 1},
+		}))
+	})
+
+	It("should run at most concurrency queries at the same time", func() {
+		var running, maxRunning int32
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				current := atomic.AddInt32(&running, 1)
+				for {
+					observed := atomic.LoadInt32(&maxRunning)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return &dynamodb.QueryOutput{Count: aws.Int64(0)}, nil
+			}).Times(6)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), true).Times(6)
+
+		multiQuery := djoemo.MultiQuery().WithConcurrency(2).WithRangeKeyName("Timestamp")
+		for i := 0; i < 6; i++ {
+			multiQuery.WithQuery(eventQuery("app"+strconv.Itoa(i), false))
+		}
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(BeNil())
+		Expect(events).To(BeEmpty())
+		Expect(atomic.LoadInt32(&maxRunning)).To(BeNumerically("<=", 2))
+	})
+
+	It("should not start further queries once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		dMock.DynamoDBAPIMock.EXPECT().
+			QueryWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+				cancel()
+				// the next query waits for the running one, so it sees the cancelled context first
+				time.Sleep(20 * time.Millisecond)
+				return nil, ctx.Err()
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, gomock.Any(), gomock.Any(), false)
+
+		multiQuery := djoemo.MultiQuery(eventQuery("app1", false), eventQuery("app2", false), eventQuery("app3", false)).
+			WithConcurrency(1).
+			WithRangeKeyName("Timestamp")
+
+		var events []Event
+		err := repository.MultiQueryWithContext(ctx, multiQuery, &events)
+
+		var multiErr *djoemo.MultiQueryError
+		Expect(errors.As(err, &multiErr)).To(BeTrue())
+		Expect(multiErr.Failures).To(HaveLen(3))
+		for _, failure := range multiErr.Failures {
+			Expect(failure.Err).To(MatchError(context.Canceled))
+		}
+	})
+
+	It("should fail if several queries have no range key to merge by", func() {
+		multiQuery := djoemo.MultiQuery(eventQuery("app1", false), eventQuery("app2", false))
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(Equal(djoemo.ErrInvalidMultiQuery))
+	})
+
+	It("should fail if the queries have different orders", func() {
+		multiQuery := djoemo.MultiQuery(eventQuery("app1", false), eventQuery("app2", true))
+
+		var events []Event
+		err := repository.MultiQueryWithContext(context.Background(), multiQuery, &events)
+		Expect(err).To(Equal(djoemo.ErrInvalidMultiQuery))
+	})
+})
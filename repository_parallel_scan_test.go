@@ -0,0 +1,227 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parallel Scan", func() {
+	const UserTableName = "UserTable"
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	key := djoemo.Key().WithTableName(UserTableName)
+
+	// expectSegments answers the scan of every segment with two pages of one item each, the item uuid is "<segment>-<page>"
+	expectSegments := func(check func(input *dynamodb.ScanInput)) {
+		expectScanPages(dMock, check, []string{"{segment}-1"}, []string{"{segment}-2"})
+	}
+
+	It("should scan all segments and handle every item", func() {
+		expectSegments(func(input *dynamodb.ScanInput) {
+			Expect(*input.TableName).To(Equal(UserTableName))
+			Expect(*input.TotalSegments).To(BeEquivalentTo(3))
+			Expect(*input.Segment).To(BeNumerically("<", 3))
+		})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+		var mu sync.Mutex
+		var uuids []string
+		err := repository.ParallelScanWithContext(context.Background(), key, 3, func(_ context.Context, item djoemo.ScanItem) error {
+			var user User
+			if err := item.UnmarshalItem(&user); err != nil {
+				return err
+			}
+			Expect(user.UUID).To(HavePrefix(strconv.Itoa(item.Segment) + "-"))
+			mu.Lock()
+			defer mu.Unlock()
+			uuids = append(uuids, user.UUID)
+			return nil
+		})
+		Expect(err).To(BeNil())
+		Expect(uuids).To(ConsistOf("0-1", "0-2", "1-1", "1-2", "2-1", "2-2"))
+	})
+
+	It("should read the projection of the key and filter the items", func() {
+		projectedKey := djoemo.Key().WithTableName(UserTableName).WithProjection("UUID")
+		expectSegments(func(input *dynamodb.ScanInput) {
+			Expect(*input.ProjectionExpression).To(Equal("#p0"))
+			Expect(*input.FilterExpression).To(Equal("(#sKRZGCY3FJFCA = :v0)"))
+			Expect(input.ExpressionAttributeNames).To(Equal(map[string]*string{
+				"#p0":            aws.String("UUID"),
+				"#sKRZGCY3FJFCA": aws.String("TraceID"),
+			}))
+			Expect(input.ExpressionAttributeValues).To(Equal(map[string]*dynamodb.AttributeValue{":v0": {S: aws.String("trace")}}))
+		})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, projectedKey, gomock.Any(), true)
+
+		err := repository.ParallelScanWithContext(context.Background(), projectedKey, 2, func(context.Context, djoemo.ScanItem) error {
+			return nil
+		}, djoemo.WithScanFilter("$ = ?", "TraceID", "trace"))
+		Expect(err).To(BeNil())
+	})
+
+	It("should report the progress of every segment", func() {
+		expectSegments(nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+		var mu sync.Mutex
+		progresses := map[int][]djoemo.ScanSegmentProgress{}
+		err := repository.ParallelScanWithContext(context.Background(), key, 2, func(context.Context, djoemo.ScanItem) error {
+			return nil
+		}, djoemo.WithScanProgress(func(progress djoemo.ScanSegmentProgress) {
+			mu.Lock()
+			defer mu.Unlock()
+			progresses[progress.Segment] = append(progresses[progress.Segment], progress)
+		}))
+		Expect(err).To(BeNil())
+		for segment := range 2 {
+			checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, Segment: segment, TotalSegments: 2}
+			firstPage := checkpoint
+			firstPage.StartKey = userItem(strconv.Itoa(segment) + "-1")
+			lastPage := checkpoint
+			lastPage.Done = true
+			Expect(progresses[segment]).To(Equal([]djoemo.ScanSegmentProgress{
+				{Segment: segment, TotalSegments: 2, Pages: 1, Items: 1, Checkpoint: firstPage},
+				{Segment: segment, TotalSegments: 2, Pages: 2, Items: 2, Done: true, Checkpoint: lastPage},
+			}))
+		}
+	})
+
+	It("should report failed segments and scan the others", func() {
+		dbErr := errors.New("some dynamo error")
+		dMock.DynamoDBAPIMock.EXPECT().
+			ScanWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+				if *input.Segment == 1 {
+					return nil, dbErr
+				}
+				return &dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid")}}, nil
+			}).Times(3)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+		var mu sync.Mutex
+		var segments []int
+		var failed djoemo.ScanSegmentProgress
+		err := repository.ParallelScanWithContext(context.Background(), key, 3, func(_ context.Context, item djoemo.ScanItem) error {
+			mu.Lock()
+			defer mu.Unlock()
+			segments = append(segments, item.Segment)
+			return nil
+		}, djoemo.WithScanProgress(func(progress djoemo.ScanSegmentProgress) {
+			if progress.Err != nil {
+				failed = progress
+			}
+		}))
+
+		var scanErr *djoemo.ParallelScanError
+		Expect(errors.As(err, &scanErr)).To(BeTrue())
+		Expect(errors.Is(err, dbErr)).To(BeTrue())
+		Expect(scanErr.Failures).To(Equal([]djoemo.ParallelScanFailure{{Segment: 1, Err: dbErr}}))
+		Expect(failed).To(Equal(djoemo.ScanSegmentProgress{
+			Segment:       1,
+			TotalSegments: 3,
+			Done:          true,
+			Err:           dbErr,
+			Checkpoint:    djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 1, TotalSegments: 3},
+		}))
+		Expect(segments).To(ConsistOf(0, 2))
+	})
+
+	It("should stop a segment at the first error of the handler", func() {
+		handleErr := errors.New("some handler error")
+		expectSegments(nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+		err := repository.ParallelScanWithContext(context.Background(), key, 2, func(_ context.Context, item djoemo.ScanItem) error {
+			if item.Segment == 0 {
+				return handleErr
+			}
+			return nil
+		})
+
+		var scanErr *djoemo.ParallelScanError
+		Expect(errors.As(err, &scanErr)).To(BeTrue())
+		Expect(scanErr.Failures).To(Equal([]djoemo.ParallelScanFailure{{Segment: 0, Err: handleErr}}))
+	})
+
+	It("should stop all segments if the context is cancelled", func() {
+		expectSegments(nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := repository.ParallelScanWithContext(ctx, key, 2, func(context.Context, djoemo.ScanItem) error {
+			cancel()
+			return nil
+		})
+
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+
+	It("should stop all segments at the result limit", func() {
+		expectSegments(nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+		var mu sync.Mutex
+		var handled int
+		err := repository.ParallelScanWithContext(context.Background(), key, 3, func(context.Context, djoemo.ScanItem) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled++
+			return nil
+		}, djoemo.WithScanLimit(4))
+		Expect(err).To(BeNil())
+		Expect(handled).To(Equal(4))
+	})
+
+	It("should send the items to the channel and close it", func() {
+		expectSegments(nil)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+		items := make(chan djoemo.ScanItem)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- repository.ParallelScanChannelWithContext(context.Background(), key, 2, items)
+		}()
+
+		var uuids []string
+		for item := range items {
+			uuids = append(uuids, *item.Item["UUID"].S)
+		}
+		Expect(<-errs).To(BeNil())
+		Expect(uuids).To(ConsistOf("0-1", "0-2", "1-1", "1-2"))
+	})
+
+	It("should fail without segments", func() {
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+		err := repository.ParallelScanWithContext(context.Background(), key, 0, func(context.Context, djoemo.ScanItem) error {
+			return nil
+		})
+		Expect(err).To(Equal(djoemo.ErrInvalidTotalSegments))
+	})
+})
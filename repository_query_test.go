@@ -2,15 +2,25 @@ package djoemo_test
 
 import (
 	"context"
+	"errors"
+	"net/url"
 
 	"go.uber.org/mock/gomock"
 
 	"github.com/adjoeio/djoemo"
 	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
+// plainQuery implements only QueryInterface, the optional interfaces of the embedded query are hidden
+type plainQuery struct {
+	djoemo.QueryInterface
+}
+
 var _ = Describe("Repository", func() {
 	const (
 		UserTableName    = "UserTable"
@@ -150,6 +160,293 @@ var _ = Describe("Repository", func() {
 				Expect(profiles[1].UUID).To(Equal("uuid2"))
 			})
 
+			It("should query items with filter expression", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(2).
+					WithFilterExpression("UserName = ? AND TraceID <> ?", "name", "trace")
+
+				userDBOutput := map[string]interface{}{
+					"UUID":     "uuid",
+					"UserName": "name",
+				}
+
+				dMock.Should().
+					Query(
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithFilterExpression("(UserName = ? AND TraceID <> ?)", "name", "trace"),
+						dMock.WithQueryOutput(userDBOutput),
+						dMock.WithLimit(2),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				err := repository.QueryWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+				Expect(users[0].UserName).To(Equal("name"))
+			})
+
+			It("should not filter queries that do not implement the filter interface", func() {
+				q := plainQuery{djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithFilterExpression("UserName = ?", "name")}
+
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+						Expect(input.FilterExpression).To(BeNil())
+						return &dynamodb.QueryOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid")}}, nil
+					})
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				err := repository.QueryWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+			})
+
+			It("should evaluate at most the search limit of a filtered query in a single request", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(2).
+					WithSearchLimit(5).
+					WithFilterExpression("UserName = ?", "name")
+
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+						Expect(*input.Limit).To(BeEquivalentTo(5))
+						return &dynamodb.QueryOutput{
+							Items:            []map[string]*dynamodb.AttributeValue{{"UUID": {S: aws.String("uuid")}, "UserName": {S: aws.String("name")}}},
+							Count:            aws.Int64(1),
+							LastEvaluatedKey: map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}},
+						}, nil
+					}).Times(1)
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				err := repository.QueryWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+			})
+
+			It("should use the search limit as page size", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(1).
+					WithSearchLimit(3)
+
+				dMock.Should().
+					Query(
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithSearchLimit(3),
+						dMock.WithQueryOutput([]map[string]interface{}{
+							{"UUID": "uuid", "UserName": "name1"},
+							{"UUID": "uuid", "UserName": "name2"},
+						}),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				token, err := repository.QueryPageWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(2))
+				Expect(token).To(BeEmpty())
+			})
+
+			It("should use the result limit as page size if the query does not implement the search limit interface", func() {
+				q := plainQuery{djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(2).
+					WithSearchLimit(1)}
+
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+						Expect(aws.Int64Value(input.Limit)).To(BeEquivalentTo(2))
+						return &dynamodb.QueryOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid")}}, nil
+					})
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				_, err := repository.QueryPageWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+			})
+
+			It("should query pages and resume from the page token", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(1)
+
+				lastEvaluatedKey := map[string]interface{}{"UUID": "uuid", "UserName": "name1"}
+
+				dMock.Should().
+					Query(
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithLimit(1),
+						dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid", "UserName": "name1"}),
+						dMock.WithLastEvaluatedKey(lastEvaluatedKey),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true).Times(2)
+
+				var users []User
+				token, err := repository.QueryPageWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+				Expect(token).NotTo(BeEmpty())
+				Expect(url.QueryEscape(token)).To(Equal(token))
+
+				dMock.Should().
+					Query(
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithLimit(1),
+						dMock.WithStartKey(lastEvaluatedKey),
+						dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid", "UserName": "name2"}),
+					).Exec()
+
+				users = nil
+				token, err = repository.QueryPageWithContext(context.Background(), q.WithPageToken(token), &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+				Expect(users[0].UserName).To(Equal("name2"))
+				Expect(token).To(BeEmpty())
+			})
+
+			It("should return error if page token is invalid", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithPageToken("not a token")
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+				var users []User
+				token, err := repository.QueryPageWithContext(context.Background(), q, &users)
+				Expect(err).To(Equal(djoemo.ErrInvalidPageToken))
+				Expect(token).To(BeEmpty())
+			})
+
+			It("should start from the beginning if the query does not implement the page token interface", func() {
+				q := plainQuery{djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithPageToken("not a token")}
+
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+						Expect(input.ExclusiveStartKey).To(BeNil())
+						return &dynamodb.QueryOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid")}}, nil
+					})
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var users []User
+				token, err := repository.QueryPageWithContext(context.Background(), q, &users)
+				Expect(err).To(BeNil())
+				Expect(users).To(HaveLen(1))
+				Expect(token).To(BeEmpty())
+			})
+
+			It("should query items with range between", func() {
+				q := djoemo.Query().WithTableName(ProfileTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithRangeKeyName("Email").
+					WithRangeBetween("a", "m")
+
+				profileDBOutput := []map[string]interface{}{
+					{"UUID": "uuid1", "Email": "b@adjoe.io"},
+				}
+
+				dMock.Should().
+					Query(
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithConditionValues(*q.RangeKeyName(), string(djoemo.Between), "a", "m"),
+						dMock.WithQueryOutput(profileDBOutput),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var profiles []Profile
+				err := repository.QueryWithContext(context.Background(), q, &profiles)
+
+				Expect(err).To(BeNil())
+				Expect(profiles).To(HaveLen(1))
+				Expect(q.RangeKey()).To(Equal("a"))
+				Expect(q.RangeValues()).To(Equal([]interface{}{"a", "m"}))
+			})
+
+			It("should compare the range key if the query does not implement the range values interface", func() {
+				q := plainQuery{djoemo.Query().WithTableName(ProfileTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithRangeKeyName("Email").
+					WithRangeValues("a", "b").
+					WithRangeOp(djoemo.Greater)}
+
+				profileDBOutput := []map[string]interface{}{
+					{"UUID": "uuid1", "Email": "b@adjoe.io"},
+				}
+
+				dMock.Should().
+					Query(
+						dMock.WithTable(q.TableName()),
+						dMock.WithCondition(*q.HashKeyName(), q.HashKey(), string(djoemo.Equal)),
+						dMock.WithCondition(*q.RangeKeyName(), "a", string(djoemo.Greater)),
+						dMock.WithQueryOutput(profileDBOutput),
+					).Exec()
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				var profiles []Profile
+				err := repository.QueryWithContext(context.Background(), q, &profiles)
+				Expect(err).To(BeNil())
+				Expect(profiles).To(HaveLen(1))
+			})
+
+			DescribeTable("should return error if range values do not match the operator",
+				func(q djoemo.QueryInterface, message string) {
+					metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+					var profiles []Profile
+					err := repository.QueryWithContext(context.Background(), q, &profiles)
+
+					var rangeErr *djoemo.RangeConditionError
+					Expect(errors.As(err, &rangeErr)).To(BeTrue())
+					Expect(errors.Is(err, djoemo.ErrInvalidRangeCondition)).To(BeTrue())
+					Expect(err.Error()).To(Equal(message))
+				},
+				Entry("between with one value",
+					djoemo.Query().WithTableName(ProfileTableName).WithHashKeyName("UUID").WithHashKey("uuid").
+						WithRangeKeyName("Email").WithRangeKey("a").WithRangeOp(djoemo.Between),
+					"operator BETWEEN expects 2 range key values, got 1"),
+				Entry("greater with two values",
+					djoemo.Query().WithTableName(ProfileTableName).WithHashKeyName("UUID").WithHashKey("uuid").
+						WithRangeKeyName("Email").WithRangeValues("a", "b").WithRangeOp(djoemo.Greater),
+					"operator GT expects 1 range key value, got 2"),
+				Entry("not equal",
+					djoemo.Query().WithTableName(ProfileTableName).WithHashKeyName("UUID").WithHashKey("uuid").
+						WithRangeKeyName("Email").WithRangeKey("a").WithRangeOp(djoemo.NotEqual),
+					"operator NE is not supported for range key conditions"),
+			)
+
 			It("should return error if output is not pointer to slice ", func() {
 				q := djoemo.Query().WithTableName(ProfileTableName).
 					WithHashKeyName("UUID").
@@ -214,5 +511,101 @@ var _ = Describe("Repository", func() {
 				})
 			})
 		})
+
+		Describe("djoemo.Query Iterator", func() {
+			queryOutput := func(lastEvaluatedKey map[string]*dynamodb.AttributeValue, userNames ...string) *dynamodb.QueryOutput {
+				output := &dynamodb.QueryOutput{LastEvaluatedKey: lastEvaluatedKey}
+				for _, userName := range userNames {
+					item, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid", "UserName": userName})
+					output.Items = append(output.Items, item)
+				}
+				return output
+			}
+
+			It("should fetch pages lazily respecting limit and order", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid").
+					WithLimit(3).
+					WithDescending()
+				lastEvaluatedKey := map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}, "UserName": {S: aws.String("name2")}}
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+				itr, err := repository.QueryIteratorWithContext(context.Background(), q)
+				Expect(err).To(BeNil())
+
+				gomock.InOrder(
+					dMock.DynamoDBAPIMock.EXPECT().
+						QueryWithContext(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+							Expect(*input.Limit).To(BeEquivalentTo(3))
+							Expect(*input.ScanIndexForward).To(BeFalse())
+							Expect(input.ExclusiveStartKey).To(BeNil())
+							return queryOutput(lastEvaluatedKey, "name1", "name2"), nil
+						}),
+					dMock.DynamoDBAPIMock.EXPECT().
+						QueryWithContext(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+							Expect(input.ExclusiveStartKey).To(Equal(lastEvaluatedKey))
+							return queryOutput(lastEvaluatedKey, "name3", "name4"), nil
+						}),
+				)
+
+				var userNames []string
+				user := User{}
+				for itr.NextItem(&user) {
+					userNames = append(userNames, user.UserName)
+				}
+				Expect(itr.Err()).To(BeNil())
+				Expect(userNames).To(Equal([]string{"name1", "name2", "name3"}))
+			})
+
+			It("should surface errors", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid")
+				dbErr := errors.New("some dynamo error")
+
+				// the iteration that ends in an error is recorded as failure
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+				dMock.DynamoDBAPIMock.EXPECT().
+					QueryWithContext(gomock.Any(), gomock.Any()).
+					Return(nil, dbErr)
+
+				itr, err := repository.GIndex("UserNameIndex").QueryIteratorWithContext(context.Background(), q)
+				Expect(err).To(BeNil())
+
+				user := User{}
+				Expect(itr.NextItem(&user)).To(BeFalse())
+				Expect(itr.Err()).To(Equal(dbErr))
+			})
+
+			It("should stop when the context is cancelled", func() {
+				q := djoemo.Query().WithTableName(UserTableName).
+					WithHashKeyName("UUID").
+					WithHashKey("uuid")
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				itr, err := repository.QueryIteratorWithContext(ctx, q)
+				Expect(err).To(BeNil())
+
+				cancel()
+				user := User{}
+				Expect(itr.NextItem(&user)).To(BeFalse())
+				Expect(itr.Err()).To(Equal(context.Canceled))
+			})
+
+			It("should return error if query is invalid", func() {
+				q := djoemo.Query().WithTableName(UserTableName).WithHashKey("uuid")
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), false)
+
+				itr, err := repository.QueryIteratorWithContext(context.Background(), q)
+				Expect(err).To(Equal(djoemo.ErrInvalidHashKeyName))
+				Expect(itr).To(BeNil())
+			})
+		})
 	})
 })
@@ -0,0 +1,254 @@
+package djoemo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scan Checkpoint", func() {
+	const (
+		UserTableName = "UserTable"
+		IndexName     = "UserNameIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+		storeMock   *mock.MockCheckpointStoreInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		storeMock = mock.NewMockCheckpointStoreInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	key := djoemo.Key().WithTableName(UserTableName)
+
+	// expectPages answers the scans with two pages of two items each, the second page starts after "uuid2"
+	expectPages := func(check func(input *dynamodb.ScanInput)) {
+		expectScanPages(dMock, check, []string{"uuid1", "uuid2"}, []string{"uuid3", "uuid4"})
+	}
+
+	Describe("Scan Iterator", func() {
+		It("should return the checkpoint of the pages read", func() {
+			expectPages(nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 2)
+			Expect(err).To(BeNil())
+			Expect(itr.Checkpoint()).To(Equal(djoemo.ScanCheckpoint{TableName: UserTableName}))
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			// the first page is not processed yet, so it is read again
+			Expect(itr.Checkpoint()).To(Equal(djoemo.ScanCheckpoint{TableName: UserTableName}))
+
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(itr.Checkpoint()).To(Equal(djoemo.ScanCheckpoint{TableName: UserTableName, StartKey: userItem("uuid2")}))
+
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(itr.NextItem(&user)).To(BeFalse())
+			Expect(itr.Checkpoint()).To(Equal(djoemo.ScanCheckpoint{TableName: UserTableName, Done: true}))
+		})
+
+		It("should resume from a checkpoint", func() {
+			expectPages(func(input *dynamodb.ScanInput) {
+				Expect(input.ExclusiveStartKey).To(Equal(userItem("uuid2")))
+			})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, StartKey: userItem("uuid2")}
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 2, djoemo.WithScanCheckpoint(checkpoint))
+			Expect(err).To(BeNil())
+
+			var user User
+			var uuids []string
+			for itr.NextItem(&user) {
+				uuids = append(uuids, user.UUID)
+			}
+			Expect(uuids).To(Equal([]string{"uuid3", "uuid4"}))
+		})
+
+		It("should resume the segment of a checkpoint of a parallel scan", func() {
+			expectPages(func(input *dynamodb.ScanInput) {
+				Expect(*input.Segment).To(BeEquivalentTo(1))
+				Expect(*input.TotalSegments).To(BeEquivalentTo(4))
+			})
+
+			checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 1, TotalSegments: 4, StartKey: userItem("uuid2")}
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanCheckpoint(checkpoint))
+			Expect(err).To(BeNil())
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(user.UUID).To(Equal("uuid3"))
+		})
+
+		It("should not read items if the checkpoint is done", func() {
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, Done: true}
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanCheckpoint(checkpoint))
+			Expect(err).To(BeNil())
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeFalse())
+		})
+
+		It("should return error if the checkpoint was taken for another scan", func() {
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, IndexName: IndexName}
+			_, err := repository.ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanCheckpoint(checkpoint))
+			Expect(errors.Is(err, djoemo.ErrInvalidScanCheckpoint)).To(BeTrue())
+		})
+
+		It("should resume from the stored checkpoint and store the checkpoint after every page", func() {
+			expectPages(nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			stored := djoemo.ScanCheckpoint{TableName: UserTableName, StartKey: userItem("uuid2")}
+			storeMock.EXPECT().Load(gomock.Any(), "backfill").Return(stored, true, nil)
+			gomock.InOrder(
+				storeMock.EXPECT().Save(gomock.Any(), "backfill", stored),
+				storeMock.EXPECT().Save(gomock.Any(), "backfill", djoemo.ScanCheckpoint{TableName: UserTableName, Done: true}),
+			)
+
+			itr, err := repository.ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanCheckpointStore(storeMock, "backfill"))
+			Expect(err).To(BeNil())
+
+			var user User
+			var uuids []string
+			for itr.NextItem(&user) {
+				uuids = append(uuids, user.UUID)
+			}
+			Expect(uuids).To(Equal([]string{"uuid3", "uuid4"}))
+		})
+
+		It("should resume a scan of a global index", func() {
+			expectPages(func(input *dynamodb.ScanInput) {
+				Expect(*input.IndexName).To(Equal(IndexName))
+				Expect(input.ExclusiveStartKey).To(Equal(userItem("uuid2")))
+			})
+
+			checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, IndexName: IndexName, StartKey: userItem("uuid2")}
+			itr, err := repository.GIndex(IndexName).ScanIteratorWithContext(context.Background(), key, 0, djoemo.WithScanCheckpoint(checkpoint))
+			Expect(err).To(BeNil())
+
+			var user User
+			Expect(itr.NextItem(&user)).To(BeTrue())
+			Expect(user.UUID).To(Equal("uuid3"))
+		})
+	})
+
+	Describe("Parallel Scan", func() {
+		It("should resume the segments from their checkpoints", func() {
+			expectPages(func(input *dynamodb.ScanInput) {
+				if *input.Segment == 0 {
+					Expect(input.ExclusiveStartKey).To(Equal(userItem("uuid2")))
+				}
+			})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			var mu sync.Mutex
+			var uuids []string
+			err := repository.ParallelScanWithContext(context.Background(), key, 3, func(_ context.Context, item djoemo.ScanItem) error {
+				mu.Lock()
+				defer mu.Unlock()
+				uuids = append(uuids, *item.Item["UUID"].S)
+				return nil
+			},
+				djoemo.WithScanCheckpoint(djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 0, TotalSegments: 3, StartKey: userItem("uuid2")}),
+				djoemo.WithScanCheckpoint(djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 1, TotalSegments: 3, Done: true}),
+			)
+			Expect(err).To(BeNil())
+			Expect(uuids).To(ConsistOf("uuid3", "uuid4", "uuid1", "uuid2", "uuid3", "uuid4"))
+		})
+
+		It("should store the checkpoints of the segments", func() {
+			expectPages(nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			storeMock.EXPECT().Load(gomock.Any(), "backfill.0-of-2").Return(djoemo.ScanCheckpoint{}, false, nil)
+			storeMock.EXPECT().Load(gomock.Any(), "backfill.1-of-2").
+				Return(djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 1, TotalSegments: 2, Done: true}, true, nil)
+			gomock.InOrder(
+				storeMock.EXPECT().Save(gomock.Any(), "backfill.0-of-2",
+					djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 0, TotalSegments: 2, StartKey: userItem("uuid2")}),
+				storeMock.EXPECT().Save(gomock.Any(), "backfill.0-of-2",
+					djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 0, TotalSegments: 2, Done: true}),
+			)
+
+			err := repository.ParallelScanWithContext(context.Background(), key, 2, func(context.Context, djoemo.ScanItem) error {
+				return nil
+			}, djoemo.WithScanCheckpointStore(storeMock, "backfill"))
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error if the checkpoint was taken for another number of segments", func() {
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			err := repository.ParallelScanWithContext(context.Background(), key, 2, func(context.Context, djoemo.ScanItem) error {
+				return nil
+			}, djoemo.WithScanCheckpoint(djoemo.ScanCheckpoint{TableName: UserTableName, Segment: 1, TotalSegments: 3}))
+			Expect(errors.Is(err, djoemo.ErrInvalidScanCheckpoint)).To(BeTrue())
+		})
+	})
+
+	Describe("File Checkpoint Store", func() {
+		It("should save, load and delete checkpoints", func() {
+			store := djoemo.NewFileCheckpointStore(GinkgoT().TempDir() + "/checkpoints")
+			checkpoint := djoemo.ScanCheckpoint{
+				TableName:     UserTableName,
+				Segment:       1,
+				TotalSegments: 2,
+				StartKey:      map[string]*dynamodb.AttributeValue{"UUID": {S: aws.String("uuid")}, "Version": {N: aws.String("3")}},
+			}
+
+			_, found, err := store.Load(context.Background(), "users/backfill")
+			Expect(err).To(BeNil())
+			Expect(found).To(BeFalse())
+
+			Expect(store.Save(context.Background(), "users/backfill", checkpoint)).To(Succeed())
+			loaded, found, err := store.Load(context.Background(), "users/backfill")
+			Expect(err).To(BeNil())
+			Expect(found).To(BeTrue())
+			Expect(loaded).To(Equal(checkpoint))
+
+			Expect(store.Delete(context.Background(), "users/backfill")).To(Succeed())
+			_, found, err = store.Load(context.Background(), "users/backfill")
+			Expect(err).To(BeNil())
+			Expect(found).To(BeFalse())
+			Expect(store.Delete(context.Background(), "users/backfill")).To(Succeed())
+		})
+	})
+
+	It("should be serializable as JSON", func() {
+		checkpoint := djoemo.ScanCheckpoint{TableName: UserTableName, IndexName: IndexName, StartKey: userItem("uuid")}
+
+		data, err := json.Marshal(checkpoint)
+		Expect(err).To(BeNil())
+
+		var decoded djoemo.ScanCheckpoint
+		Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(checkpoint))
+	})
+})
@@ -0,0 +1,216 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sequence", func() {
+	const (
+		UserTableName = "UserTable"
+		IndexName     = "UserNameIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		repository  djoemo.RepositoryInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	Describe("ScanAll", func() {
+		key := djoemo.Key().WithTableName(UserTableName)
+
+		It("should yield the items of all pages", func() {
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+						Expect(input.ExclusiveStartKey).To(BeNil())
+						return &dynamodb.ScanOutput{
+							Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1", "UserName", "name1")},
+							LastEvaluatedKey: userItem("uuid1"),
+						}, nil
+					}),
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, input *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+						Expect(input.ExclusiveStartKey).To(Equal(userItem("uuid1")))
+						return &dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid2")}}, nil
+					}),
+			)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			var users []User
+			for user, err := range djoemo.ScanAll[User](context.Background(), repository, key) {
+				Expect(err).To(BeNil())
+				users = append(users, user)
+			}
+
+			// every item is unmarshalled into a new user, so no attributes of the previous item are left
+			Expect(users).To(Equal([]User{{UUID: "uuid1", UserName: "name1"}, {UUID: "uuid2"}}))
+		})
+
+		It("should cancel the scan when the loop is left early", func() {
+			var scanCtx context.Context
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ *dynamodb.ScanInput, _ ...any) (*dynamodb.ScanOutput, error) {
+					scanCtx = ctx
+					return &dynamodb.ScanOutput{
+						Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1"), userItem("uuid2")},
+						LastEvaluatedKey: userItem("uuid2"),
+					}, nil
+				})
+			// leaving the loop early is no failure
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			var users []User
+			for user, err := range djoemo.ScanAll[User](context.Background(), repository, key) {
+				Expect(err).To(BeNil())
+				users = append(users, user)
+				break
+			}
+
+			Expect(users).To(Equal([]User{{UUID: "uuid1"}}))
+			Expect(scanCtx.Err()).To(Equal(context.Canceled))
+		})
+
+		It("should yield the error that stopped the scan", func() {
+			dbErr := errors.New("some dynamo error")
+			gomock.InOrder(
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ScanOutput{
+						Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1")},
+						LastEvaluatedKey: userItem("uuid1"),
+					}, nil),
+				dMock.DynamoDBAPIMock.EXPECT().
+					ScanWithContext(gomock.Any(), gomock.Any()).
+					Return(nil, dbErr),
+			)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			var users []User
+			var errs []error
+			for user, err := range djoemo.ScanAll[User](context.Background(), repository, key) {
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				users = append(users, user)
+			}
+
+			Expect(users).To(Equal([]User{{UUID: "uuid1"}}))
+			Expect(errs).To(Equal([]error{dbErr}))
+		})
+
+		It("should yield the error if the scan is invalid", func() {
+			invalidKey := djoemo.Key()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, invalidKey, gomock.Any(), false)
+
+			var errs []error
+			for _, err := range djoemo.ScanAll[User](context.Background(), repository, invalidKey) {
+				errs = append(errs, err)
+			}
+
+			Expect(errs).To(Equal([]error{djoemo.ErrInvalidTableName}))
+		})
+
+		It("should scan the table of a typed repository", func() {
+			dMock.DynamoDBAPIMock.EXPECT().
+				ScanWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.ScanOutput{Items: []map[string]*dynamodb.AttributeValue{userItem("uuid1", "UserName", "name1")}}, nil)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			users := djoemo.NewTypedRepository[User](repository, djoemo.TableSpec{TableName: UserTableName, HashKeyName: "UUID"})
+			var userNames []string
+			for user, err := range users.ScanAll(context.Background(), key) {
+				Expect(err).To(BeNil())
+				userNames = append(userNames, user.UserName)
+			}
+
+			Expect(userNames).To(Equal([]string{"name1"}))
+		})
+	})
+
+	Describe("QueryAll", func() {
+		query := djoemo.Query().WithTableName(UserTableName).
+			WithHashKeyName("UserName").
+			WithHashKey("name1")
+
+		It("should yield the items of a query of a global index", func() {
+			dMock.DynamoDBAPIMock.EXPECT().
+				QueryWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+					Expect(aws.StringValue(input.IndexName)).To(Equal(IndexName))
+					return &dynamodb.QueryOutput{
+						Items: []map[string]*dynamodb.AttributeValue{userItem("uuid1", "UserName", "name1"), userItem("uuid2", "UserName", "name1")},
+					}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, query, gomock.Any(), true)
+
+			var uuids []string
+			for user, err := range djoemo.QueryAll[User](context.Background(), repository.GIndex(IndexName), query) {
+				Expect(err).To(BeNil())
+				uuids = append(uuids, user.UUID)
+			}
+
+			Expect(uuids).To(Equal([]string{"uuid1", "uuid2"}))
+		})
+
+		It("should yield the error that stopped the query", func() {
+			dbErr := errors.New("some dynamo error")
+			dMock.DynamoDBAPIMock.EXPECT().
+				QueryWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, dbErr)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, query, gomock.Any(), false)
+
+			users := djoemo.NewTypedRepository[User](repository, djoemo.TableSpec{TableName: UserTableName, HashKeyName: "UUID"})
+			var errs []error
+			for _, err := range users.GIndex(IndexName).QueryAll(context.Background(), query) {
+				errs = append(errs, err)
+			}
+
+			Expect(errs).To(Equal([]error{dbErr}))
+		})
+
+		It("should cancel the query when the loop is left early", func() {
+			var queryCtx context.Context
+			dMock.DynamoDBAPIMock.EXPECT().
+				QueryWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ *dynamodb.QueryInput, _ ...any) (*dynamodb.QueryOutput, error) {
+					queryCtx = ctx
+					return &dynamodb.QueryOutput{
+						Items:            []map[string]*dynamodb.AttributeValue{userItem("uuid1", "UserName", "name1"), userItem("uuid2", "UserName", "name1")},
+						LastEvaluatedKey: userItem("uuid2", "UserName", "name1"),
+					}, nil
+				})
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, query, gomock.Any(), true)
+
+			for _, err := range djoemo.QueryAll[User](context.Background(), repository, query) {
+				Expect(err).To(BeNil())
+				break
+			}
+
+			Expect(queryCtx.Err()).To(Equal(context.Canceled))
+		})
+	})
+})
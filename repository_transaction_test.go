@@ -0,0 +1,454 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+var _ = Describe("Repository Transactions", func() {
+	const (
+		UserTableName    = "UserTable"
+		ProfileTableName = "ProfileTable"
+	)
+
+	var (
+		dAPIMock    *mock.MockDynamoDBAPI
+		repository  djoemo.RepositoryInterface
+		logMock     *mock.MockLogInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock = mock.NewMockDynamoDBAPI(mockCtrl)
+		logMock = mock.NewMockLogInterface(mockCtrl)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithLog(logMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	Describe("TransactWriteItemsWithContext", func() {
+		It("should do nothing for an empty transaction", func() {
+			err := repository.TransactWriteItemsWithContext(context.Background(), djoemo.TransactWrite())
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail with invalid key", func() {
+			valid := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+			invalid := djoemo.Key().WithHashKeyName("UUID").WithHashKey("uuid")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, valid, gomock.Any(), false)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, invalid, gomock.Any(), false)
+
+			tx := djoemo.TransactWrite().
+				Put(valid, &User{UUID: "uuid"}).
+				Delete(invalid)
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+			Expect(err).To(Equal(djoemo.ErrInvalidTableName))
+		})
+
+		It("should fail for a condition check without condition", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, key, gomock.Any(), false)
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), djoemo.TransactWrite().ConditionCheck(key, ""))
+			Expect(err).To(Equal(djoemo.ErrMissingConditionExpression))
+		})
+
+		It("should commit put, update, delete and condition check across tables", func() {
+			debit := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("debit")
+			credit := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("credit")
+			profile := djoemo.Key().WithTableName(ProfileTableName).
+				WithHashKeyName("UUID").WithHashKey("uuid").
+				WithRangeKeyName("Email").WithRangeKey("mail@adjoe.io")
+			user := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...any) (*dynamodb.TransactWriteItemsOutput, error) {
+					Expect(input.TransactItems).To(HaveLen(4))
+
+					Expect(*input.TransactItems[0].Update.TableName).To(Equal(UserTableName))
+					Expect(*input.TransactItems[0].Update.ConditionExpression).To(ContainSubstring(">="))
+					Expect(*input.TransactItems[1].Update.Key["UUID"].S).To(Equal("credit"))
+					Expect(input.TransactItems[1].Update.ConditionExpression).To(BeNil())
+
+					Expect(*input.TransactItems[2].Delete.TableName).To(Equal(ProfileTableName))
+					Expect(*input.TransactItems[2].Delete.Key["Email"].S).To(Equal("mail@adjoe.io"))
+
+					Expect(*input.TransactItems[3].ConditionCheck.ConditionExpression).To(Equal("(attribute_exists(UUID))"))
+					return &dynamodb.TransactWriteItemsOutput{}, nil
+				})
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, debit, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, credit, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, profile, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, user, gomock.Any(), true)
+
+			tx := djoemo.TransactWrite().
+				ConditionalUpdate(debit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": -10}}, "Balance >= ?", 10).
+				Update(credit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": 10}}).
+				Delete(profile).
+				ConditionCheck(user, "attribute_exists(UUID)")
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+			Expect(err).To(BeNil())
+		})
+
+		It("should commit a conditional put", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...any) (*dynamodb.TransactWriteItemsOutput, error) {
+					Expect(input.TransactItems).To(HaveLen(1))
+					Expect(*input.TransactItems[0].Put.Item["UserName"].S).To(Equal("name"))
+					Expect(*input.TransactItems[0].Put.ConditionExpression).To(Equal("(attribute_not_exists(UUID))"))
+					return &dynamodb.TransactWriteItemsOutput{}, nil
+				})
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, key, gomock.Any(), true)
+
+			tx := djoemo.TransactWrite().ConditionalPut(key, &User{UUID: "uuid", UserName: "name"}, "attribute_not_exists(UUID)")
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return error and record failure for every key", func() {
+			first := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("first")
+			second := djoemo.Key().WithTableName(ProfileTableName).WithHashKeyName("UUID").WithHashKey("second")
+			dbErr := errors.New("some dynamo error")
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, dbErr)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, first, gomock.Any(), false)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, second, gomock.Any(), false)
+
+			tx := djoemo.TransactWrite().
+				Put(first, &User{UUID: "first"}).
+				Put(second, &Profile{UUID: "second"})
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+			Expect(err).To(Equal(dbErr))
+		})
+	})
+
+	Describe("TransactGetItemsWithContext", func() {
+		It("should do nothing for an empty transaction", func() {
+			missing, err := repository.TransactGetItemsWithContext(context.Background(), djoemo.TransactGet())
+			Expect(err).To(BeNil())
+			Expect(missing).To(BeEmpty())
+		})
+
+		It("should fail with invalid key", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			missing, err := repository.TransactGetItemsWithContext(context.Background(), djoemo.TransactGet().Get(key, &User{}))
+			Expect(err).To(Equal(djoemo.ErrInvalidHashKeyValue))
+			Expect(missing).To(BeNil())
+		})
+
+		It("should read items across tables and report missing keys", func() {
+			userKey := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+			missingKey := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("missing")
+			profileKey := djoemo.Key().WithTableName(ProfileTableName).
+				WithHashKeyName("UUID").WithHashKey("uuid").
+				WithRangeKeyName("Email").WithRangeKey("mail@adjoe.io")
+
+			userItem, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid", "UserName": "name"})
+			profileItem, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "uuid", "Email": "mail@adjoe.io"})
+
+			dAPIMock.EXPECT().
+				TransactGetItemsWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.TransactGetItemsInput, _ ...any) (*dynamodb.TransactGetItemsOutput, error) {
+					Expect(input.TransactItems).To(HaveLen(3))
+					Expect(*input.TransactItems[0].Get.TableName).To(Equal(UserTableName))
+					Expect(*input.TransactItems[1].Get.Key["UUID"].S).To(Equal("missing"))
+					Expect(*input.TransactItems[2].Get.Key["Email"].S).To(Equal("mail@adjoe.io"))
+					return &dynamodb.TransactGetItemsOutput{
+						Responses: []*dynamodb.ItemResponse{
+							{Item: userItem},
+							{},
+							{Item: profileItem},
+						},
+					}, nil
+				})
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, userKey, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, missingKey, gomock.Any(), true)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, profileKey, gomock.Any(), true)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().WithField(djoemo.TableName, UserTableName).Return(logMock)
+			logMock.EXPECT().Info(djoemo.ErrNoItemFound.Error())
+
+			user := &User{}
+			other := &User{}
+			profile := &Profile{}
+			tx := djoemo.TransactGet().
+				Get(userKey, user).
+				Get(missingKey, other).
+				Get(profileKey, profile)
+
+			missing, err := repository.TransactGetItemsWithContext(context.Background(), tx)
+			Expect(err).To(BeNil())
+			Expect(missing).To(Equal([]djoemo.KeyInterface{missingKey}))
+			Expect(user.UserName).To(Equal("name"))
+			Expect(other.UUID).To(BeEmpty())
+			Expect(profile.Email).To(Equal("mail@adjoe.io"))
+		})
+
+		It("should report all keys missing if no item exists", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+
+			dAPIMock.EXPECT().
+				TransactGetItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(&dynamodb.TransactGetItemsOutput{Responses: []*dynamodb.ItemResponse{{}}}, nil)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().WithField(djoemo.TableName, UserTableName).Return(logMock)
+			logMock.EXPECT().Info(djoemo.ErrNoItemFound.Error())
+
+			missing, err := repository.TransactGetItemsWithContext(context.Background(), djoemo.TransactGet().Get(key, &User{}))
+			Expect(err).To(BeNil())
+			Expect(missing).To(Equal([]djoemo.KeyInterface{key}))
+		})
+
+		It("should return error and record failure", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("uuid")
+			dbErr := errors.New("some dynamo error")
+
+			dAPIMock.EXPECT().
+				TransactGetItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, dbErr)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			missing, err := repository.TransactGetItemsWithContext(context.Background(), djoemo.TransactGet().Get(key, &User{}))
+			Expect(err).To(Equal(dbErr))
+			Expect(missing).To(BeNil())
+		})
+	})
+
+	Describe("TransactionCanceledError", func() {
+		var (
+			debit  djoemo.KeyInterface
+			credit djoemo.KeyInterface
+		)
+
+		BeforeEach(func() {
+			debit = djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("debit")
+			credit = djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("credit")
+		})
+
+		It("should map cancellation reasons to the keys of the transaction", func() {
+			cancelErr := awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]", nil)
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, cancelErr)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, debit, gomock.Any(), false)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, credit, gomock.Any(), false)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+			tx := djoemo.TransactWrite().
+				Update(debit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": -10}}).
+				ConditionalUpdate(credit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": 10}}, "attribute_exists(UUID)")
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+
+			var txErr *djoemo.TransactionCanceledError
+			Expect(errors.As(err, &txErr)).To(BeTrue())
+			Expect(errors.Is(err, cancelErr)).To(BeTrue())
+			Expect(txErr.ConditionFailed()).To(BeTrue())
+			Expect(txErr.Conflict()).To(BeFalse())
+			Expect(txErr.Reasons).To(HaveLen(1))
+
+			_, found := txErr.Reason(debit)
+			Expect(found).To(BeFalse())
+			reason, found := txErr.Reason(credit)
+			Expect(found).To(BeTrue())
+			Expect(reason.Code).To(Equal(djoemo.CancellationReasonConditionalCheckFailed))
+			Expect(reason.UnmarshalCurrentItem(&User{})).To(Equal(djoemo.ErrNoItemFound))
+		})
+
+		It("should read the current item of a failed condition", func() {
+			cancelErr := awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, ThrottlingError]", nil)
+			stored, _ := dynamodbattribute.MarshalMap(map[string]interface{}{"UUID": "debit", "UserName": "stored"})
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, cancelErr)
+			dAPIMock.EXPECT().
+				GetItemWithContext(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, input *dynamodb.GetItemInput, _ ...any) (*dynamodb.GetItemOutput, error) {
+					Expect(*input.Key["UUID"].S).To(Equal("debit"))
+					Expect(*input.ConsistentRead).To(BeTrue())
+					return &dynamodb.GetItemOutput{Item: stored}, nil
+				})
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, debit, gomock.Any(), false)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, credit, gomock.Any(), false)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+			tx := djoemo.TransactWrite().
+				ConditionalUpdate(debit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": -10}}, "Balance >= ?", 10).
+				Update(credit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": 10}}).
+				WithCurrentItemReads()
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+
+			var txErr *djoemo.TransactionCanceledError
+			Expect(errors.As(err, &txErr)).To(BeTrue())
+			Expect(txErr.Throttled()).To(BeTrue())
+
+			reason, found := txErr.Reason(debit)
+			Expect(found).To(BeTrue())
+			user := &User{}
+			Expect(reason.UnmarshalCurrentItem(user)).To(BeNil())
+			Expect(user.UserName).To(Equal("stored"))
+
+			reason, found = txErr.Reason(credit)
+			Expect(found).To(BeTrue())
+			Expect(reason.Throttled()).To(BeTrue())
+			Expect(reason.CurrentItem).To(BeNil())
+		})
+
+		It("should return the error of reading the current item of a failed condition", func() {
+			cancelErr := awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed]", nil)
+			readErr := errors.New("some dynamo error")
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, cancelErr)
+			dAPIMock.EXPECT().
+				GetItemWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, readErr)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, debit, gomock.Any(), false)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+			tx := djoemo.TransactWrite().
+				ConditionalUpdate(debit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": -10}}, "Balance >= ?", 10).
+				WithCurrentItemReads()
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+
+			var txErr *djoemo.TransactionCanceledError
+			Expect(errors.As(err, &txErr)).To(BeTrue())
+			reason, found := txErr.Reason(debit)
+			Expect(found).To(BeTrue())
+			Expect(reason.CurrentItemErr).To(Equal(readErr))
+			Expect(reason.UnmarshalCurrentItem(&User{})).To(Equal(readErr))
+		})
+
+		It("should find the reason of an equal key", func() {
+			cancelErr := awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed]", nil)
+
+			dAPIMock.EXPECT().
+				TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, cancelErr)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, debit, gomock.Any(), false)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+			tx := djoemo.TransactWrite().
+				ConditionalUpdate(debit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": -10}}, "Balance >= ?", 10)
+
+			err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+
+			var txErr *djoemo.TransactionCanceledError
+			Expect(errors.As(err, &txErr)).To(BeTrue())
+			// the key is not the one of the transaction, but identifies the same item
+			_, found := txErr.Reason(djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("debit"))
+			Expect(found).To(BeTrue())
+			_, found = txErr.Reason(djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID").WithHashKey("credit"))
+			Expect(found).To(BeFalse())
+		})
+
+		DescribeTable("should parse the cancellation reasons of the error message",
+			func(message string, codes []string) {
+				dAPIMock.EXPECT().
+					TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+					Return(nil, fmt.Errorf("wrapped: %w", awserr.New(dynamodb.ErrCodeTransactionCanceledException, message, nil)))
+
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, debit, gomock.Any(), false)
+				metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, credit, gomock.Any(), false)
+				logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+				logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+				tx := djoemo.TransactWrite().
+					Update(debit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": -10}}).
+					Update(credit, djoemo.UpdateExpressions{djoemo.Add: {"Balance": 10}})
+
+				err := repository.TransactWriteItemsWithContext(context.Background(), tx)
+
+				// wrapped errors are recognized as cancelled transactions too
+				var txErr *djoemo.TransactionCanceledError
+				Expect(errors.As(err, &txErr)).To(BeTrue())
+				var reasonCodes []string
+				for _, reason := range txErr.Reasons {
+					reasonCodes = append(reasonCodes, reason.Code)
+				}
+				Expect(reasonCodes).To(Equal(codes))
+			},
+			Entry("no brackets", "Transaction cancelled", nil),
+			Entry("empty brackets", "Transaction cancelled []", nil),
+			Entry("unbalanced brackets", "Transaction cancelled ] [", nil),
+			Entry("codes of all items", "Transaction cancelled [TransactionConflict, ThrottlingError]",
+				[]string{djoemo.CancellationReasonTransactionConflict, djoemo.CancellationReasonThrottling}),
+			Entry("codes after other brackets", "Transaction [1] cancelled [None,ConditionalCheckFailed]",
+				[]string{djoemo.CancellationReasonConditionalCheckFailed}),
+		)
+
+		It("should map cancellation reasons of read transactions", func() {
+			cancelErr := awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [TransactionConflict, None]", nil)
+
+			dAPIMock.EXPECT().
+				TransactGetItemsWithContext(gomock.Any(), gomock.Any()).
+				Return(nil, cancelErr)
+
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, debit, gomock.Any(), false)
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, credit, gomock.Any(), false)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+			tx := djoemo.TransactGet().
+				Get(debit, &User{}).
+				Get(credit, &User{})
+
+			_, err := repository.TransactGetItemsWithContext(context.Background(), tx)
+
+			var txErr *djoemo.TransactionCanceledError
+			Expect(errors.As(err, &txErr)).To(BeTrue())
+			Expect(txErr.Conflict()).To(BeTrue())
+			reason, found := txErr.Reason(debit)
+			Expect(found).To(BeTrue())
+			Expect(reason.Conflict()).To(BeTrue())
+		})
+	})
+})
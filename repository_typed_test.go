@@ -0,0 +1,163 @@
+package djoemo_test
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Typed Repository", func() {
+	const (
+		UserTableName = "UserTable"
+		IndexName     = "UserNameIndex"
+	)
+
+	var (
+		dMock       mock.DynamoMock
+		users       *djoemo.TypedRepository[User]
+		metricsMock *mock.MockMetricsInterface
+		logMock     *mock.MockLogInterface
+	)
+
+	userTable := djoemo.TableSpec{TableName: UserTableName, HashKeyName: "UUID"}
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock := mock.NewMockDynamoDBAPI(mockCtrl)
+		dMock = mock.NewDynamoMock(dAPIMock)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository := djoemo.NewRepository(dAPIMock)
+		repository.WithMetrics(metricsMock)
+		logMock = mock.NewMockLogInterface(mockCtrl)
+		repository.WithLog(logMock)
+		users = djoemo.NewTypedRepository[User](repository, userTable)
+	})
+
+	Describe("Get", func() {
+		It("should get typed item", func() {
+			key := users.Table().Key("uuid")
+
+			dMock.Should().
+				Get(
+					dMock.WithTable(UserTableName),
+					dMock.WithHash("UUID", "uuid"),
+					dMock.WithGetOutput(map[string]interface{}{
+						"UUID":     "uuid",
+						"UserName": "name",
+					}),
+				).Exec()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+
+			user, found, err := users.Get(context.Background(), key)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeTrue())
+			Expect(user.UUID).To(Equal("uuid"))
+			Expect(user.UserName).To(Equal("name"))
+		})
+
+		It("should return nil if item is not found", func() {
+			key := users.Table().Key("uuid")
+
+			dMock.Should().
+				Get(
+					dMock.WithTable(UserTableName),
+					dMock.WithHash("UUID", "uuid"),
+					dMock.WithGetOutput(nil),
+				).Exec()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), true)
+			logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+			logMock.EXPECT().WithField(djoemo.TableName, UserTableName).Return(logMock)
+			logMock.EXPECT().Info(djoemo.ErrNoItemFound.Error())
+
+			user, found, err := users.Get(context.Background(), key)
+			Expect(err).To(BeNil())
+			Expect(found).To(BeFalse())
+			Expect(user).To(BeNil())
+		})
+
+		It("should return error of invalid key", func() {
+			key := djoemo.Key().WithTableName(UserTableName).WithHashKeyName("UUID")
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, key, gomock.Any(), false)
+
+			user, found, err := users.Get(context.Background(), key)
+			Expect(err).To(Equal(djoemo.ErrInvalidHashKeyValue))
+			Expect(found).To(BeFalse())
+			Expect(user).To(BeNil())
+		})
+	})
+
+	Describe("Query", func() {
+		It("should query typed items", func() {
+			q := users.Table().Query("uuid").WithLimit(2)
+
+			dMock.Should().
+				Query(
+					dMock.WithTable(UserTableName),
+					dMock.WithCondition("UUID", "uuid", string(djoemo.Equal)),
+					dMock.WithLimit(2),
+					dMock.WithQueryOutput([]map[string]interface{}{
+						{"UUID": "uuid", "UserName": "name1"},
+						{"UUID": "uuid", "UserName": "name2"},
+					}),
+				).Exec()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			result, err := users.Query(context.Background(), q)
+			Expect(err).To(BeNil())
+			Expect(result).To(HaveLen(2))
+			Expect(result[1].UserName).To(Equal("name2"))
+		})
+
+		It("should query typed items of a global index", func() {
+			q := djoemo.Query().WithTableName(UserTableName).WithHashKeyName("UserName").WithHashKey("name1")
+
+			dMock.Should().
+				Query(
+					dMock.WithTable(UserTableName),
+					dMock.WithIndex(IndexName),
+					dMock.WithCondition("UserName", "name1", string(djoemo.Equal)),
+					dMock.WithQueryOutput(map[string]interface{}{"UUID": "uuid", "UserName": "name1"}),
+				).Exec()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpRead, q, gomock.Any(), true)
+
+			result, err := users.GIndex(IndexName).Query(context.Background(), q)
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal([]User{{UUID: "uuid", UserName: "name1"}}))
+		})
+	})
+
+	Describe("Save", func() {
+		It("should save item with the key of the item", func() {
+			dMock.Should().
+				Save(
+					dMock.WithTable(UserTableName),
+					dMock.WithInput(map[string]interface{}{
+						"UUID":      "uuid",
+						"UserName":  "name",
+						"UpdatedAt": "0001-01-01T00:00:00Z",
+						"CreatedAt": "0001-01-01T00:00:00Z",
+					}),
+				).Exec()
+			metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpCommit, gomock.Any(), gomock.Any(), true).
+				Do(func(_ context.Context, _ string, key djoemo.KeyInterface, _ time.Duration, _ bool) {
+					Expect(key.TableName()).To(Equal(UserTableName))
+					Expect(*key.HashKeyName()).To(Equal("UUID"))
+					Expect(key.HashKey()).To(Equal("uuid"))
+				})
+
+			err := users.Save(context.Background(), &User{UUID: "uuid", UserName: "name"})
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail if the item has no hash key", func() {
+			err := users.Save(context.Background(), &User{UserName: "name"})
+			Expect(err).To(Equal(djoemo.ErrInvalidHashKeyValue))
+		})
+	})
+})
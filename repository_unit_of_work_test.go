@@ -0,0 +1,209 @@
+package djoemo_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adjoeio/djoemo"
+	"github.com/adjoeio/djoemo/mock"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+)
+
+// Wallet model with optimistic locking
+type Wallet struct {
+	djoemo.Model
+	UUID    string
+	Balance int
+}
+
+var _ = Describe("Repository UnitOfWork", func() {
+	const WalletTableName = "WalletTable"
+
+	var (
+		dAPIMock    *mock.MockDynamoDBAPI
+		repository  djoemo.RepositoryInterface
+		logMock     *mock.MockLogInterface
+		metricsMock *mock.MockMetricsInterface
+	)
+
+	walletKey := func(uuid string) djoemo.KeyInterface {
+		return djoemo.Key().WithTableName(WalletTableName).WithHashKeyName("UUID").WithHashKey(uuid)
+	}
+
+	BeforeEach(func() {
+		mockCtrl := gomock.NewController(GinkgoT())
+		dAPIMock = mock.NewMockDynamoDBAPI(mockCtrl)
+		logMock = mock.NewMockLogInterface(mockCtrl)
+		metricsMock = mock.NewMockMetricsInterface(mockCtrl)
+		repository = djoemo.NewRepository(dAPIMock)
+		repository.WithLog(logMock)
+		repository.WithMetrics(metricsMock)
+	})
+
+	It("should not call dynamo if nothing changed", func() {
+		uow := repository.UnitOfWork()
+		uow.RegisterLoaded(walletKey("unchanged"), &Wallet{UUID: "unchanged", Balance: 10})
+
+		committed, err := uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeTrue())
+	})
+
+	It("should commit changed, new and deleted items guarded by their version", func() {
+		changedKey := walletKey("changed")
+		newKey := walletKey("new")
+		deletedKey := walletKey("deleted")
+
+		changed := &Wallet{UUID: "changed", Balance: 10, Model: djoemo.Model{Version: 3}}
+		unchanged := &Wallet{UUID: "unchanged", Balance: 10, Model: djoemo.Model{Version: 1}}
+		created := &Wallet{UUID: "new", Balance: 5}
+		deleted := &Wallet{UUID: "deleted", Model: djoemo.Model{Version: 7}}
+
+		dAPIMock.EXPECT().
+			TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...any) (*dynamodb.TransactWriteItemsOutput, error) {
+				Expect(input.TransactItems).To(HaveLen(3))
+
+				put := input.TransactItems[0].Put
+				Expect(*put.Item["UUID"].S).To(Equal("changed"))
+				Expect(*put.Item["Version"].N).To(Equal("4"))
+				Expect(*put.ConditionExpression).To(Equal("(attribute_not_exists(Version) OR Version = :v0)"))
+				Expect(*put.ExpressionAttributeValues[":v0"].N).To(Equal("3"))
+
+				put = input.TransactItems[1].Put
+				Expect(*put.Item["UUID"].S).To(Equal("new"))
+				Expect(*put.Item["Version"].N).To(Equal("1"))
+				// new items must not overwrite items saved without version
+				Expect(put.ExpressionAttributeNames).To(HaveLen(1))
+				for placeholder, name := range put.ExpressionAttributeNames {
+					Expect(*put.ConditionExpression).To(Equal("(attribute_not_exists(" + placeholder + "))"))
+					Expect(*name).To(Equal("UUID"))
+				}
+
+				del := input.TransactItems[2].Delete
+				Expect(*del.Key["UUID"].S).To(Equal("deleted"))
+				Expect(*del.ExpressionAttributeValues[":v0"].N).To(Equal("7"))
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			})
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, changedKey, gomock.Any(), true)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, newKey, gomock.Any(), true)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, deletedKey, gomock.Any(), true)
+
+		uow := repository.UnitOfWork()
+		uow.RegisterLoaded(changedKey, changed)
+		uow.RegisterLoaded(walletKey("unchanged"), unchanged)
+		uow.RegisterNew(newKey, created)
+		uow.RegisterLoaded(deletedKey, deleted)
+		uow.RegisterDeleted(deletedKey, deleted)
+
+		changed.Balance = 20
+
+		committed, err := uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeTrue())
+		Expect(changed.Version).To(BeEquivalentTo(4))
+		Expect(changed.UpdatedAt).NotTo(BeNil())
+		Expect(created.CreatedAt).NotTo(BeNil())
+		Expect(unchanged.Version).To(BeEquivalentTo(1))
+
+		// the unit of work is empty after commit
+		committed, err = uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeTrue())
+	})
+
+	It("should return false if a version does not match", func() {
+		key := walletKey("changed")
+		cancelErr := awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+			"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed]", nil)
+
+		dAPIMock.EXPECT().
+			TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+			Return(nil, cancelErr)
+
+		createdKey := walletKey("created")
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, createdKey, gomock.Any(), false)
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, key, gomock.Any(), false)
+		logMock.EXPECT().WithContext(gomock.Any()).Return(logMock)
+		logMock.EXPECT().Info(dynamodb.ErrCodeTransactionCanceledException)
+
+		created := &Wallet{UUID: "created"}
+		changed := &Wallet{UUID: "changed", Balance: 10, Model: djoemo.Model{Version: 3}}
+		uow := repository.UnitOfWork()
+		uow.RegisterNew(createdKey, created)
+		uow.RegisterLoaded(key, changed)
+		changed.Balance = 20
+
+		committed, err := uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeFalse())
+		// the items are restored and the stale unit of work is emptied, so a retry does not send a version that cannot match
+		Expect(changed.Version).To(BeEquivalentTo(3))
+		Expect(changed.UpdatedAt).To(BeNil())
+		Expect(changed.Balance).To(Equal(20))
+		Expect(created.Version).To(BeEquivalentTo(0))
+		Expect(created.CreatedAt).To(BeNil())
+
+		committed, err = uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeTrue())
+	})
+
+	It("should fail the commit if a key is invalid and be usable afterwards", func() {
+		uow := repository.UnitOfWork()
+		uow.RegisterLoaded(walletKey("unchanged"), &Wallet{UUID: "unchanged"})
+		uow.RegisterNew(djoemo.Key().WithTableName(WalletTableName).WithHashKey("new"), &Wallet{UUID: "new"})
+
+		committed, err := uow.CommitWithContext(context.Background())
+		Expect(err).To(Equal(djoemo.ErrInvalidHashKeyName))
+		Expect(committed).To(BeFalse())
+
+		// the failed registrations are dropped, so the unit of work can be used again
+		uow.RegisterLoaded(walletKey("unchanged"), &Wallet{UUID: "unchanged"})
+		committed, err = uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeTrue())
+	})
+
+	It("should return error in case of error", func() {
+		key := walletKey("changed")
+		dbErr := errors.New("some dynamo error")
+
+		dAPIMock.EXPECT().
+			TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+			Return(nil, dbErr)
+
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, key, gomock.Any(), false)
+
+		changed := &Wallet{UUID: "changed", Balance: 10, Model: djoemo.Model{Version: 3}}
+		uow := repository.UnitOfWork()
+		uow.RegisterLoaded(key, changed)
+		changed.Balance = 20
+
+		committed, err := uow.CommitWithContext(context.Background())
+		Expect(err).To(Equal(dbErr))
+		Expect(committed).To(BeFalse())
+		Expect(changed.Version).To(BeEquivalentTo(3))
+
+		// the retry sends the same version and condition again
+		dAPIMock.EXPECT().
+			TransactWriteItemsWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input *dynamodb.TransactWriteItemsInput, _ ...any) (*dynamodb.TransactWriteItemsOutput, error) {
+				put := input.TransactItems[0].Put
+				Expect(*put.Item["Version"].N).To(Equal("4"))
+				Expect(*put.ExpressionAttributeValues[":v0"].N).To(Equal("3"))
+				return &dynamodb.TransactWriteItemsOutput{}, nil
+			})
+		metricsMock.EXPECT().Record(gomock.Any(), djoemo.OpTransaction, key, gomock.Any(), true)
+
+		committed, err = uow.CommitWithContext(context.Background())
+		Expect(err).To(BeNil())
+		Expect(committed).To(BeTrue())
+		Expect(changed.Version).To(BeEquivalentTo(4))
+	})
+})
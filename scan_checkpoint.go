@@ -0,0 +1,112 @@
+package djoemo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// ScanCheckpoint is the position of a scan; it is serializable as JSON, so a scan can be resumed after a restart
+// by passing the checkpoint to WithScanCheckpoint. Checkpoints are taken at page boundaries: a scan resumed from
+// a checkpoint taken in the middle of a page reads that page again, so its items may be returned twice
+type ScanCheckpoint struct {
+	// TableName is the name of the scanned table
+	TableName string `json:"tableName"`
+	// IndexName is the name of the scanned index, empty for scans of the table
+	IndexName string `json:"indexName,omitempty"`
+	// Segment is the segment of a parallel scan the checkpoint belongs to
+	Segment int `json:"segment,omitempty"`
+	// TotalSegments is the number of segments of a parallel scan, 0 if the scan is not segmented
+	TotalSegments int `json:"totalSegments,omitempty"`
+	// StartKey is the key the scan resumes after, empty if the scan starts at the beginning
+	StartKey map[string]*dynamodb.AttributeValue `json:"startKey,omitempty"`
+	// Done is true if all items were read
+	Done bool `json:"done,omitempty"`
+}
+
+// validate returns ErrInvalidScanCheckpoint if the checkpoint was not taken for a scan of the table and index
+func (c ScanCheckpoint) validate(tableName, indexName string) error {
+	if c.TableName != tableName || c.IndexName != indexName {
+		return fmt.Errorf("%w: taken for table %q and index %q", ErrInvalidScanCheckpoint, c.TableName, c.IndexName)
+	}
+	if c.TotalSegments < 0 || c.Segment < 0 || (c.TotalSegments > 0 && c.Segment >= c.TotalSegments) {
+		return fmt.Errorf("%w: segment %d of %d", ErrInvalidScanCheckpoint, c.Segment, c.TotalSegments)
+	}
+
+	return nil
+}
+
+// segmentCheckpointName returns the name the checkpoint of a segment of a parallel scan is stored under
+func segmentCheckpointName(name string, segment, totalSegments int) string {
+	return fmt.Sprintf("%s.%d-of-%d", name, segment, totalSegments)
+}
+
+// iteratorCheckpoint returns the checkpoint a scan iterator of the table or index starts from: the checkpoint passed
+// with WithScanCheckpoint, or else the one of the checkpoint store; nil if the scan starts at the beginning
+func (options scanOptions) iteratorCheckpoint(ctx context.Context, tableName, indexName string) (*ScanCheckpoint, error) {
+	if len(options.checkpoints) > 1 {
+		return nil, fmt.Errorf("%w: a scan iterator resumes from a single checkpoint", ErrInvalidScanCheckpoint)
+	}
+
+	var checkpoint *ScanCheckpoint
+	if len(options.checkpoints) == 1 {
+		checkpoint = &options.checkpoints[0]
+	} else if options.checkpointStore != nil {
+		stored, found, err := options.checkpointStore.Load(ctx, options.checkpointName)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			checkpoint = &stored
+		}
+	}
+
+	if checkpoint != nil {
+		if err := checkpoint.validate(tableName, indexName); err != nil {
+			return nil, err
+		}
+	}
+
+	return checkpoint, nil
+}
+
+// segmentCheckpoints returns the checkpoints the segments of a parallel scan of the table start from, by segment: the checkpoints
+// passed with WithScanCheckpoint, or else the ones of the checkpoint store; segments without checkpoint start at the beginning
+func (options scanOptions) segmentCheckpoints(ctx context.Context, tableName string, totalSegments int) (map[int]*ScanCheckpoint, error) {
+	checkpoints := make(map[int]*ScanCheckpoint)
+	for _, checkpoint := range options.checkpoints {
+		if err := checkpoint.validate(tableName, ""); err != nil {
+			return nil, err
+		}
+		if checkpoint.TotalSegments != totalSegments {
+			return nil, fmt.Errorf("%w: taken for %d segments", ErrInvalidScanCheckpoint, checkpoint.TotalSegments)
+		}
+		checkpoints[checkpoint.Segment] = &checkpoint
+	}
+
+	if options.checkpointStore == nil {
+		return checkpoints, nil
+	}
+	for segment := range totalSegments {
+		if checkpoints[segment] != nil {
+			continue
+		}
+		stored, found, err := options.checkpointStore.Load(ctx, segmentCheckpointName(options.checkpointName, segment, totalSegments))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		if err := stored.validate(tableName, ""); err != nil {
+			return nil, err
+		}
+		if stored.Segment != segment || stored.TotalSegments != totalSegments {
+			return nil, fmt.Errorf("%w: segment %d of %d", ErrInvalidScanCheckpoint, stored.Segment, stored.TotalSegments)
+		}
+		checkpoints[segment] = &stored
+	}
+
+	return checkpoints, nil
+}
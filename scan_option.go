@@ -0,0 +1,107 @@
+package djoemo
+
+import "context"
+
+// ScanOption configures the scan of ScanIteratorWithContext and ParallelScanWithContext
+type ScanOption func(*scanOptions)
+
+type scanOptions struct {
+	limit      int64
+	filter     string
+	filterArgs []any
+	progress   func(ScanSegmentProgress)
+
+	checkpoints     []ScanCheckpoint
+	checkpointStore CheckpointStoreInterface
+	checkpointName  string
+
+	limiter *CapacityLimiter
+}
+
+// WithScanLimit sets the result limit of a scan; at most limit items are returned, pages are read until the limit is reached.
+// The search limit of ScanIteratorWithContext, in contrast, caps the items evaluated per request.
+// For parallel scans the limit caps the items of all segments together
+func WithScanLimit(limit int64) ScanOption {
+	return func(options *scanOptions) {
+		options.limit = limit
+	}
+}
+
+// WithScanFilter sets a filter expression that restricts the items of a scan; every ? in it is replaced by a value and every $
+// by an attribute name from args, in order. The filter is applied after the items are read, so it does not reduce the consumed capacity
+func WithScanFilter(filter string, args ...any) ScanOption {
+	return func(options *scanOptions) {
+		options.filter = filter
+		options.filterArgs = args
+	}
+}
+
+// WithScanProgress sets a callback that receives the progress of every segment of a parallel scan; it is called concurrently
+// by the segments, after every page and once more when a segment is done
+func WithScanProgress(progress func(ScanSegmentProgress)) ScanOption {
+	return func(options *scanOptions) {
+		options.progress = progress
+	}
+}
+
+// WithScanCapacityLimiter caps the read capacity consumed per second by a scan at the read rate of limiter; the segments of a parallel
+// scan share the budget. It takes precedence over the capacity limiter of the context. A limiter can be shared by several scans
+// and batch operations, so they stay below the rate together
+func WithScanCapacityLimiter(limiter *CapacityLimiter) ScanOption {
+	return func(options *scanOptions) {
+		options.limiter = limiter
+	}
+}
+
+// reads returns the read budget of the scan: the one of the limiter of the options, or else the one of the limiter of the context;
+// nil if the reads are not limited
+func (options scanOptions) reads(ctx context.Context) *capacityBucket {
+	if options.limiter != nil {
+		return options.limiter.reads()
+	}
+
+	return capacityLimiterFromContext(ctx).reads()
+}
+
+func newScanOptions(opts []ScanOption) scanOptions {
+	var options scanOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+// WithScanCheckpoint resumes a scan from a checkpoint taken by an earlier scan of the same table or index. A scan iterator resumed
+// from the checkpoint of a segment scans only that segment; a parallel scan accepts one checkpoint per segment, segments without
+// checkpoint start at the beginning
+func WithScanCheckpoint(checkpoint ScanCheckpoint) ScanOption {
+	return func(options *scanOptions) {
+		options.checkpoints = append(options.checkpoints, checkpoint)
+	}
+}
+
+// WithScanCheckpointStore makes a scan resumable: the scan starts from the checkpoint stored under name, if there is one,
+// and stores its checkpoint after every page. The segments of a parallel scan store their checkpoints under name suffixed
+// with the segment. Checkpoints passed with WithScanCheckpoint take precedence over the stored ones
+func WithScanCheckpointStore(store CheckpointStoreInterface, name string) ScanOption {
+	return func(options *scanOptions) {
+		options.checkpointStore = store
+		options.checkpointName = name
+	}
+}
+
+// filterExpression builds the filter expression of the options
+// returns nil if there is no filter, returns ErrInvalidExpressionArgs if the args do not match the placeholders of the filter
+func (options scanOptions) filterExpression() (*expression, error) {
+	if options.filter == "" {
+		return nil, nil
+	}
+
+	expr, err := buildExpression(options.filter, options.filterArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &expr, nil
+}
@@ -0,0 +1,132 @@
+package djoemo
+
+import (
+	"context"
+	"maps"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/guregu/dynamo"
+)
+
+// newScanInput builds the input of a scan of the table of key, or of the index if indexName is set, restricted to
+// the projection of key and the filter of options; searchLimit is the number of items read per request, 0 reads pages of up to 1MB
+// returns ErrInvalidExpressionArgs if the args of the filter do not match its placeholders
+func newScanInput(key KeyInterface, indexName string, searchLimit int64, consistent bool, options scanOptions, cc *dynamo.ConsumedCapacity) (dynamodb.ScanInput, error) {
+	input := dynamodb.ScanInput{
+		TableName:              aws.String(key.TableName()),
+		ConsistentRead:         aws.Bool(consistent),
+		ReturnConsumedCapacity: returnConsumedCapacity(cc),
+	}
+	if indexName != "" {
+		input.IndexName = aws.String(indexName)
+	}
+	if searchLimit > 0 {
+		input.Limit = aws.Int64(searchLimit)
+	}
+
+	names := make(map[string]*string)
+	if projection := keyProjection(key); len(projection) > 0 {
+		var expression string
+		expression, names = projectionExpression(projection)
+		input.ProjectionExpression = aws.String(expression)
+	}
+	filter, err := options.filterExpression()
+	if err != nil {
+		return dynamodb.ScanInput{}, err
+	}
+	if filter != nil {
+		maps.Copy(names, filter.names)
+		input.FilterExpression = aws.String(filter.expression)
+		input.ExpressionAttributeValues = filter.expressionValues()
+	}
+	if len(names) > 0 {
+		input.ExpressionAttributeNames = names
+	}
+
+	return input, nil
+}
+
+// scanPager reads the pages of a scan, or of a segment of a parallel scan, with the raw client, guregu/dynamo does not
+// support segments; it keeps the position of the scan for checkpoints
+type scanPager struct {
+	client        dynamodbiface.DynamoDBAPI
+	input         dynamodb.ScanInput
+	segment       int
+	totalSegments int
+	// reads is the read capacity budget of the scan, nil if the capacity is not limited
+	reads *capacityBucket
+
+	// pageStartKey is the start key of the current page, nextStartKey the start key of the next page
+	pageStartKey map[string]*dynamodb.AttributeValue
+	nextStartKey map[string]*dynamodb.AttributeValue
+	// done is set once the last page is read
+	done bool
+}
+
+// newScanPager creates the pager of segment of the scan of input, totalSegments is 0 if the scan is not segmented;
+// the scan starts from checkpoint if it is not nil and its pages are limited by the read capacity budget reads if it is not nil
+func newScanPager(client dynamodbiface.DynamoDBAPI, input dynamodb.ScanInput, segment, totalSegments int, checkpoint *ScanCheckpoint, reads *capacityBucket) *scanPager {
+	if totalSegments > 0 {
+		input.Segment = aws.Int64(int64(segment))
+		input.TotalSegments = aws.Int64(int64(totalSegments))
+	}
+	if reads != nil && input.ReturnConsumedCapacity == nil {
+		input.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityTotal)
+	}
+
+	pager := &scanPager{
+		client:        client,
+		input:         input,
+		segment:       segment,
+		totalSegments: totalSegments,
+		reads:         reads,
+	}
+	if checkpoint != nil {
+		pager.nextStartKey = checkpoint.StartKey
+		pager.done = checkpoint.Done
+	}
+
+	return pager
+}
+
+// next reads the next page, waiting for the read capacity budget if it is limited; it must not be called once the pager is done
+func (p *scanPager) next(ctx context.Context) (*dynamodb.ScanOutput, error) {
+	p.input.ExclusiveStartKey = p.nextStartKey
+	var output *dynamodb.ScanOutput
+	err := limitedRequest(ctx, p.reads, func() (float64, error) {
+		var err error
+		if output, err = p.client.ScanWithContext(ctx, &p.input); err != nil {
+			return 0, err
+		}
+		return capacityUnits(output.ConsumedCapacity), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.pageStartKey = p.nextStartKey
+	p.nextStartKey = output.LastEvaluatedKey
+	p.done = len(output.LastEvaluatedKey) == 0
+
+	return output, nil
+}
+
+// checkpoint returns the position of the scan; if pageDone is set, all items of the current page are processed
+// and the scan resumes with the next page, otherwise the current page is read again
+func (p *scanPager) checkpoint(pageDone bool) ScanCheckpoint {
+	checkpoint := ScanCheckpoint{
+		TableName:     aws.StringValue(p.input.TableName),
+		IndexName:     aws.StringValue(p.input.IndexName),
+		Segment:       p.segment,
+		TotalSegments: p.totalSegments,
+		StartKey:      p.pageStartKey,
+	}
+	if pageDone {
+		checkpoint.StartKey = p.nextStartKey
+		checkpoint.Done = p.done
+	}
+
+	return checkpoint
+}
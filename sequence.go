@@ -0,0 +1,74 @@
+package djoemo
+
+import (
+	"context"
+	"iter"
+)
+
+// scanIteratorSource is implemented by repositories and global secondary indexes
+type scanIteratorSource interface {
+	ScanIteratorWithContext(ctx context.Context, key KeyInterface, searchLimit int64, opts ...ScanOption) (ScanIteratorInterface, error)
+}
+
+// queryIteratorSource is implemented by repositories, global and local secondary indexes
+type queryIteratorSource interface {
+	QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error)
+}
+
+// ScanAll returns the items of a scan of the table of key, or of the global secondary index if source is an index, as a sequence
+// to range over; source is a repository or a global secondary index, the scan reads pages of up to 1MB and accepts the options
+// of ScanIteratorWithContext. Every item is unmarshalled into a new T. If the scan fails, the error is yielded as last element
+// with the zero value of T; breaking the loop cancels the context of the scan, so no further pages are read
+func ScanAll[T any](ctx context.Context, source scanIteratorSource, key KeyInterface, opts ...ScanOption) iter.Seq2[T, error] {
+	return sequence[T](ctx, func(ctx context.Context) (IteratorInterface, error) {
+		return source.ScanIteratorWithContext(ctx, key, 0, opts...)
+	})
+}
+
+// QueryAll returns the items of query as a sequence to range over; source is a repository, a global or a local secondary index.
+// Every item is unmarshalled into a new T. If the query fails, the error is yielded as last element with the zero value of T;
+// breaking the loop cancels the context of the query, so no further pages are read
+func QueryAll[T any](ctx context.Context, source queryIteratorSource, query QueryInterface) iter.Seq2[T, error] {
+	return sequence[T](ctx, func(ctx context.Context) (IteratorInterface, error) {
+		return source.QueryIteratorWithContext(ctx, query)
+	})
+}
+
+// sequence returns the items of the iterator created by newIterator as a sequence; the iterator is created when the sequence
+// is ranged over, with a context that is cancelled when the loop ends
+func sequence[T any](ctx context.Context, newIterator func(ctx context.Context) (IteratorInterface, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var zero T
+		itr, err := newIterator(ctx)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for {
+			var item T
+			if !itr.NextItem(&item) {
+				break
+			}
+			if !yield(item, nil) {
+				// the loop was left early, the iteration ends without error before its context is cancelled
+				if stopper, ok := itr.(iterationStopper); ok {
+					stopper.stop(nil)
+				}
+				return
+			}
+		}
+
+		if err := itr.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// iterationStopper is implemented by the iterators of this package, stop ends the iteration with err
+type iterationStopper interface {
+	stop(err error) bool
+}
@@ -0,0 +1,164 @@
+package djoemo
+
+// TransactOperation is the kind of write applied to an item of a transaction
+type TransactOperation string
+
+// Operations supported in write transactions.
+const (
+	TransactPut            TransactOperation = "Put"
+	TransactUpdate         TransactOperation = "Update"
+	TransactDelete         TransactOperation = "Delete"
+	TransactConditionCheck TransactOperation = "ConditionCheck"
+)
+
+// TransactWriteItem is a single operation of a write transaction
+type TransactWriteItem struct {
+	// Operation is the kind of write applied to the item
+	Operation TransactOperation
+	// Key identifies the item and the table it belongs to
+	Key KeyInterface
+	// Item is the item to be saved; only used by TransactPut
+	Item any
+	// UpdateExpressions are the updates applied to the item; only used by TransactUpdate
+	UpdateExpressions UpdateExpressions
+	// ConditionExpression must evaluate to true for the transaction to succeed; required by TransactConditionCheck
+	ConditionExpression string
+	// ConditionArgs are the values substituted into the placeholders of ConditionExpression
+	ConditionArgs []any
+}
+
+type transactWrite struct {
+	items            []TransactWriteItem
+	readCurrentItems bool
+}
+
+// TransactWrite factory method to create struct that implements transact write interface
+func TransactWrite() *transactWrite {
+	return &transactWrite{}
+}
+
+// Put adds a put of item to the transaction
+func (tx *transactWrite) Put(key KeyInterface, item any) *transactWrite {
+	return tx.add(TransactWriteItem{Operation: TransactPut, Key: key, Item: item})
+}
+
+// ConditionalPut adds a put of item to the transaction, that is only applied if the condition evaluates to true
+func (tx *transactWrite) ConditionalPut(key KeyInterface, item any, conditionExpression string, conditionArgs ...any) *transactWrite {
+	return tx.add(TransactWriteItem{
+		Operation:           TransactPut,
+		Key:                 key,
+		Item:                item,
+		ConditionExpression: conditionExpression,
+		ConditionArgs:       conditionArgs,
+	})
+}
+
+// Update adds an update of the item identified by key to the transaction
+func (tx *transactWrite) Update(key KeyInterface, updateExpressions UpdateExpressions) *transactWrite {
+	return tx.add(TransactWriteItem{Operation: TransactUpdate, Key: key, UpdateExpressions: updateExpressions})
+}
+
+// ConditionalUpdate adds an update of the item identified by key to the transaction, that is only applied if the condition evaluates to true
+func (tx *transactWrite) ConditionalUpdate(key KeyInterface, updateExpressions UpdateExpressions, conditionExpression string, conditionArgs ...any) *transactWrite {
+	return tx.add(TransactWriteItem{
+		Operation:           TransactUpdate,
+		Key:                 key,
+		UpdateExpressions:   updateExpressions,
+		ConditionExpression: conditionExpression,
+		ConditionArgs:       conditionArgs,
+	})
+}
+
+// Delete adds a delete of the item identified by key to the transaction
+func (tx *transactWrite) Delete(key KeyInterface) *transactWrite {
+	return tx.add(TransactWriteItem{Operation: TransactDelete, Key: key})
+}
+
+// ConditionalDelete adds a delete of the item identified by key to the transaction, that is only applied if the condition evaluates to true
+func (tx *transactWrite) ConditionalDelete(key KeyInterface, conditionExpression string, conditionArgs ...any) *transactWrite {
+	return tx.add(TransactWriteItem{
+		Operation:           TransactDelete,
+		Key:                 key,
+		ConditionExpression: conditionExpression,
+		ConditionArgs:       conditionArgs,
+	})
+}
+
+// ConditionCheck adds a condition on the item identified by key, that must evaluate to true for the transaction to succeed
+func (tx *transactWrite) ConditionCheck(key KeyInterface, conditionExpression string, conditionArgs ...any) *transactWrite {
+	return tx.add(TransactWriteItem{
+		Operation:           TransactConditionCheck,
+		Key:                 key,
+		ConditionExpression: conditionExpression,
+		ConditionArgs:       conditionArgs,
+	})
+}
+
+// WithCurrentItemReads set djoemo transaction to read the current items of failed conditions into TransactionCanceledError
+// if it is cancelled; every item costs a strongly consistent read after the cancellation, so it may differ from the version
+// that failed the condition
+func (tx *transactWrite) WithCurrentItemReads() *transactWrite {
+	tx.readCurrentItems = true
+	return tx
+}
+
+// Items returns the operations of the transaction in the order they were added
+func (tx *transactWrite) Items() []TransactWriteItem {
+	return tx.items
+}
+
+// ReadCurrentItems returns true if the current items of failed conditions are read after a cancellation
+func (tx *transactWrite) ReadCurrentItems() bool {
+	return tx.readCurrentItems
+}
+
+func (tx *transactWrite) add(item TransactWriteItem) *transactWrite {
+	tx.items = append(tx.items, item)
+	return tx
+}
+
+func transactWriteKeys(tx TransactWriteInterface) []KeyInterface {
+	items := tx.Items()
+	keys := make([]KeyInterface, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	return keys
+}
+
+// TransactGetItem is a single read of a read transaction
+type TransactGetItem struct {
+	// Key identifies the item and the table it belongs to
+	Key KeyInterface
+	// Out is a pointer the item is unmarshalled into, if it exists
+	Out any
+}
+
+type transactGet struct {
+	items []TransactGetItem
+}
+
+// TransactGet factory method to create struct that implements transact get interface
+func TransactGet() *transactGet {
+	return &transactGet{}
+}
+
+// Get adds a read of the item identified by key to the transaction; the item will be unmarshalled into out
+func (tx *transactGet) Get(key KeyInterface, out any) *transactGet {
+	tx.items = append(tx.items, TransactGetItem{Key: key, Out: out})
+	return tx
+}
+
+// Items returns the reads of the transaction in the order they were added
+func (tx *transactGet) Items() []TransactGetItem {
+	return tx.items
+}
+
+func transactGetKeys(tx TransactGetInterface) []KeyInterface {
+	items := tx.Items()
+	keys := make([]KeyInterface, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	return keys
+}
@@ -0,0 +1,170 @@
+package djoemo
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// Codes reported by dynamodb for the items of a cancelled transaction.
+const (
+	CancellationReasonNone                          = "None"
+	CancellationReasonConditionalCheckFailed        = "ConditionalCheckFailed"
+	CancellationReasonTransactionConflict           = "TransactionConflict"
+	CancellationReasonThrottling                    = "ThrottlingError"
+	CancellationReasonProvisionedThroughputExceeded = "ProvisionedThroughputExceeded"
+	CancellationReasonValidation                    = "ValidationError"
+	CancellationReasonItemCollectionSizeLimit       = "ItemCollectionSizeLimitExceeded"
+)
+
+// TransactionCancellationReason is the reason why a single item caused a transaction to be cancelled
+type TransactionCancellationReason struct {
+	// Key identifies the item that caused the cancellation
+	Key KeyInterface
+	// Code is the cancellation reason reported by dynamodb, e.g. CancellationReasonConditionalCheckFailed
+	Code string
+	// CurrentItem is the item as read by a separate strongly consistent read after the transaction was cancelled, not the
+	// version that failed the condition, which aws-sdk-go v1 does not expose. It is only read for failed conditions of
+	// transactions created WithCurrentItemReads, nil otherwise or if the item does not exist
+	CurrentItem map[string]*dynamodb.AttributeValue
+	// CurrentItemErr is the error of reading CurrentItem, nil if it was read or does not exist
+	CurrentItemErr error
+}
+
+// ConditionFailed returns true if the condition of the item evaluated to false
+func (r TransactionCancellationReason) ConditionFailed() bool {
+	return r.Code == CancellationReasonConditionalCheckFailed
+}
+
+// Conflict returns true if the item is being modified by another transaction
+func (r TransactionCancellationReason) Conflict() bool {
+	return r.Code == CancellationReasonTransactionConflict
+}
+
+// Throttled returns true if the request for the item was throttled
+func (r TransactionCancellationReason) Throttled() bool {
+	return r.Code == CancellationReasonThrottling || r.Code == CancellationReasonProvisionedThroughputExceeded
+}
+
+// ValidationFailed returns true if the operation on the item is invalid
+func (r TransactionCancellationReason) ValidationFailed() bool {
+	return r.Code == CancellationReasonValidation || r.Code == CancellationReasonItemCollectionSizeLimit
+}
+
+// UnmarshalCurrentItem unmarshals the current item into out
+// returns the error of reading the item if it failed, returns ErrNoItemFound if the item is not available
+func (r TransactionCancellationReason) UnmarshalCurrentItem(out any) error {
+	if r.CurrentItemErr != nil {
+		return r.CurrentItemErr
+	}
+	if r.CurrentItem == nil {
+		return ErrNoItemFound
+	}
+	return dynamo.UnmarshalItem(r.CurrentItem, out)
+}
+
+// TransactionCanceledError is returned if dynamodb cancels a transaction; it holds the reason for every item that caused the cancellation
+type TransactionCanceledError struct {
+	// Reasons are the cancellation reasons of the items that caused the cancellation, in transaction order
+	Reasons []TransactionCancellationReason
+	err     awserr.Error
+}
+
+// Error returns the error message reported by dynamodb
+func (e *TransactionCanceledError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the original aws error
+func (e *TransactionCanceledError) Unwrap() error {
+	return e.err
+}
+
+// ConditionFailed returns true if at least one condition of the transaction evaluated to false
+func (e *TransactionCanceledError) ConditionFailed() bool {
+	return e.any(TransactionCancellationReason.ConditionFailed)
+}
+
+// Conflict returns true if at least one item is being modified by another transaction
+func (e *TransactionCanceledError) Conflict() bool {
+	return e.any(TransactionCancellationReason.Conflict)
+}
+
+// Throttled returns true if at least one request of the transaction was throttled
+func (e *TransactionCanceledError) Throttled() bool {
+	return e.any(TransactionCancellationReason.Throttled)
+}
+
+// ValidationFailed returns true if at least one operation of the transaction is invalid
+func (e *TransactionCanceledError) ValidationFailed() bool {
+	return e.any(TransactionCancellationReason.ValidationFailed)
+}
+
+// Reason returns the cancellation reason of the item identified by key
+// returns false if the item did not cause the cancellation
+func (e *TransactionCanceledError) Reason(key KeyInterface) (TransactionCancellationReason, bool) {
+	for _, reason := range e.Reasons {
+		if reason.Key != nil && isSameKey(reason.Key, key) {
+			return reason, true
+		}
+	}
+	return TransactionCancellationReason{}, false
+}
+
+func (e *TransactionCanceledError) any(match func(TransactionCancellationReason) bool) bool {
+	for _, reason := range e.Reasons {
+		if match(reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTransactionCanceledError maps the cancellation reasons of a cancelled transaction to the keys of its items.
+// aws-sdk-go v1 does not decode the reasons of the response, so they are taken from the error message,
+// which lists the code of every item in transaction order, e.g. "... specific reasons [None, ConditionalCheckFailed]"
+func newTransactionCanceledError(err awserr.Error, keys []KeyInterface) *TransactionCanceledError {
+	txErr := &TransactionCanceledError{err: err}
+
+	for i, code := range cancellationReasonCodes(err.Message()) {
+		if code == "" || code == CancellationReasonNone {
+			continue
+		}
+
+		reason := TransactionCancellationReason{Code: code}
+		if i < len(keys) {
+			reason.Key = keys[i]
+		}
+		txErr.Reasons = append(txErr.Reasons, reason)
+	}
+
+	return txErr
+}
+
+// cancellationReasonCodes returns the codes listed between the last brackets of the message of a cancelled transaction,
+// one per item in transaction order; returns nil if the message lists no codes
+func cancellationReasonCodes(message string) []string {
+	start := strings.LastIndex(message, "[")
+	end := strings.LastIndex(message, "]")
+	if start == -1 || end < start {
+		return nil
+	}
+
+	codes := strings.Split(message[start+1:end], ",")
+	for i, code := range codes {
+		codes[i] = strings.TrimSpace(code)
+	}
+	return codes
+}
+
+// isTransactionCanceled returns the aws error of a cancelled transaction, err may wrap it
+func isTransactionCanceled(err error) (awserr.Error, bool) {
+	var awsError awserr.Error
+	if !errors.As(err, &awsError) || awsError.Code() != dynamodb.ErrCodeTransactionCanceledException {
+		return nil, false
+	}
+	return awsError, true
+}
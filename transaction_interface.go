@@ -0,0 +1,15 @@
+package djoemo
+
+// TransactWriteInterface provides an interface for djoemo write transactions used to commit several items atomically
+type TransactWriteInterface interface {
+	// Items returns the operations of the transaction in the order they were added
+	Items() []TransactWriteItem
+	// ReadCurrentItems returns true if the current items of failed conditions are read after a cancellation
+	ReadCurrentItems() bool
+}
+
+// TransactGetInterface provides an interface for djoemo read transactions used to read several items from a consistent snapshot
+type TransactGetInterface interface {
+	// Items returns the reads of the transaction in the order they were added
+	Items() []TransactGetItem
+}
@@ -0,0 +1,295 @@
+package djoemo
+
+import (
+	"context"
+	"iter"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+// TableSpec describes the table of a typed repository
+type TableSpec struct {
+	// TableName is the name of the table
+	TableName string
+	// HashKeyName is the name of the hash key of the table
+	HashKeyName string
+	// RangeKeyName is the name of the range key of the table; empty if the table has no range key
+	RangeKeyName string
+}
+
+// Key returns a key of the table for the given hash key; the range key can be added with WithRangeKey
+func (spec TableSpec) Key(hashKey any) *key {
+	k := Key().WithTableName(spec.TableName).WithHashKeyName(spec.HashKeyName).WithHashKey(hashKey)
+	if spec.RangeKeyName != "" {
+		k = k.WithRangeKeyName(spec.RangeKeyName)
+	}
+
+	return k
+}
+
+// Query returns a query of the table for the given hash key; range key conditions can be added with WithRangeKey and WithRangeOp
+func (spec TableSpec) Query(hashKey any) *query {
+	q := Query().WithTableName(spec.TableName).WithHashKeyName(spec.HashKeyName).WithHashKey(hashKey)
+	if spec.RangeKeyName != "" {
+		q = q.WithRangeKeyName(spec.RangeKeyName)
+	}
+
+	return q
+}
+
+// itemKey returns the key of item, the key values are taken from the marshalled item
+// returns ErrInvalidHashKeyValue if the item has no hash key
+func (spec TableSpec) itemKey(item any) (*key, error) {
+	av, err := dynamo.MarshalItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	hashKey := keyValue(av[spec.HashKeyName])
+	if hashKey == nil {
+		return nil, ErrInvalidHashKeyValue
+	}
+	k := spec.Key(hashKey)
+
+	if spec.RangeKeyName != "" {
+		if rangeKey := keyValue(av[spec.RangeKeyName]); rangeKey != nil {
+			k = k.WithRangeKey(rangeKey)
+		}
+	}
+
+	return k, nil
+}
+
+// keyValue returns the value of a key attribute, numbers are returned as int64 or, if they are not integral, as float64
+// returns nil if the attribute is missing or not a valid key type
+func keyValue(av *dynamodb.AttributeValue) any {
+	switch {
+	case av == nil:
+		return nil
+	case av.S != nil:
+		return *av.S
+	case av.N != nil:
+		if n, err := strconv.ParseInt(*av.N, 10, 64); err == nil {
+			return n
+		}
+		if f, err := strconv.ParseFloat(*av.N, 64); err == nil {
+			return f
+		}
+	case av.B != nil:
+		return av.B
+	}
+
+	return nil
+}
+
+// TypedRepository reads and writes items of type T of a single table; it is built on a repository
+// and type checks the items at compile time instead of at runtime
+type TypedRepository[T any] struct {
+	repository RepositoryInterface
+	table      TableSpec
+}
+
+// NewTypedRepository factory method for typed repository of the table described by table
+func NewTypedRepository[T any](repository RepositoryInterface, table TableSpec) *TypedRepository[T] {
+	return &TypedRepository[T]{
+		repository: repository,
+		table:      table,
+	}
+}
+
+// Table returns the table of the repository, it is used to build keys and queries
+func (r *TypedRepository[T]) Table() TableSpec {
+	return r.table
+}
+
+// Repository returns the untyped repository
+func (r *TypedRepository[T]) Repository() RepositoryInterface {
+	return r.repository
+}
+
+// GIndex returns the typed repository of a global secondary index of the table
+func (r *TypedRepository[T]) GIndex(name string) *TypedIndex[T] {
+	return &TypedIndex[T]{index: r.repository.GIndex(name)}
+}
+
+// LIndex returns the typed repository of a local secondary index of the table
+func (r *TypedRepository[T]) LIndex(name string) *TypedIndex[T] {
+	return &TypedIndex[T]{index: r.repository.LIndex(name)}
+}
+
+// Get gets the item of key
+// returns the item and true if it is found, returns nil and false if no item found, returns nil, false and an error in case of error
+func (r *TypedRepository[T]) Get(ctx context.Context, key KeyInterface) (*T, bool, error) {
+	item := new(T)
+	found, err := r.repository.GetItemWithContext(ctx, key, item)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	return item, true, nil
+}
+
+// GetItems gets all items of the hash key of key
+// returns the items, empty if no items found, returns nil and an error in case of error
+func (r *TypedRepository[T]) GetItems(ctx context.Context, key KeyInterface) ([]T, error) {
+	var items []T
+	if _, err := r.repository.GetItemsWithContext(ctx, key, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// BatchGet gets the items of keys; keys of items that do not exist are skipped
+// returns the found items, returns nil and an error in case of error
+func (r *TypedRepository[T]) BatchGet(ctx context.Context, keys []KeyInterface) ([]T, error) {
+	var items []T
+	if _, err := r.repository.BatchGetItemsWithContext(ctx, keys, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Query gets the items of query
+// returns the items, returns nil and an error in case of error
+func (r *TypedRepository[T]) Query(ctx context.Context, query QueryInterface) ([]T, error) {
+	var items []T
+	if err := r.repository.QueryWithContext(ctx, query, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// QueryPage gets a single page of the items of query, like QueryPageWithContext
+// returns the items and the token of the next page, empty if there are no more pages, returns nil, an empty token and an error in case of error
+func (r *TypedRepository[T]) QueryPage(ctx context.Context, query QueryInterface) ([]T, string, error) {
+	var items []T
+	nextPageToken, err := r.repository.QueryPageWithContext(ctx, query, &items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return items, nextPageToken, nil
+}
+
+// ScanAll returns the items of a scan of the table as a sequence to range over, like the package level ScanAll
+func (r *TypedRepository[T]) ScanAll(ctx context.Context, key KeyInterface, opts ...ScanOption) iter.Seq2[T, error] {
+	return ScanAll[T](ctx, r.repository, key, opts...)
+}
+
+// QueryAll returns the items of query as a sequence to range over, like the package level QueryAll
+func (r *TypedRepository[T]) QueryAll(ctx context.Context, query QueryInterface) iter.Seq2[T, error] {
+	return QueryAll[T](ctx, r.repository, query)
+}
+
+// Save saves item; the key is taken from the hash key and range key attributes of the item
+// returns error in case of error
+func (r *TypedRepository[T]) Save(ctx context.Context, item *T) error {
+	key, err := r.table.itemKey(item)
+	if err != nil {
+		return err
+	}
+
+	return r.repository.SaveItemWithContext(ctx, key, item)
+}
+
+// SaveItems batch saves items
+// returns error in case of error
+func (r *TypedRepository[T]) SaveItems(ctx context.Context, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	key, err := r.table.itemKey(&items[0])
+	if err != nil {
+		return err
+	}
+
+	return r.repository.SaveItemsWithContext(ctx, key, items)
+}
+
+// OptimisticLockSave saves item if the version on the server matches the version of item; T must embed Model
+// returns true if the item is saved, returns false and nil if the version does not match, returns false and an error in case of error
+func (r *TypedRepository[T]) OptimisticLockSave(ctx context.Context, item *T) (bool, error) {
+	key, err := r.table.itemKey(item)
+	if err != nil {
+		return false, err
+	}
+
+	return r.repository.OptimisticLockSaveWithContext(ctx, key, item)
+}
+
+// Delete deletes the item of key
+// returns error in case of error
+func (r *TypedRepository[T]) Delete(ctx context.Context, key KeyInterface) error {
+	return r.repository.DeleteItemWithContext(ctx, key)
+}
+
+// typedIndexReader is implemented by global and local secondary indexes
+type typedIndexReader interface {
+	GetItemWithContext(ctx context.Context, key KeyInterface, item any) (bool, error)
+	GetItemsWithContext(ctx context.Context, key KeyInterface, items any) (bool, error)
+	QueryWithContext(ctx context.Context, query QueryInterface, items any) error
+	QueryPageWithContext(ctx context.Context, query QueryInterface, items any) (string, error)
+	QueryIteratorWithContext(ctx context.Context, query QueryInterface) (QueryIteratorInterface, error)
+}
+
+// TypedIndex reads items of type T from a secondary index of a typed repository
+type TypedIndex[T any] struct {
+	index typedIndexReader
+}
+
+// Get gets the item of key from the index
+// returns the item and true if it is found, returns nil and false if no item found, returns nil, false and an error in case of error
+func (i *TypedIndex[T]) Get(ctx context.Context, key KeyInterface) (*T, bool, error) {
+	item := new(T)
+	found, err := i.index.GetItemWithContext(ctx, key, item)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	return item, true, nil
+}
+
+// GetItems gets all items of the hash key of key from the index
+// returns the items, empty if no items found, returns nil and an error in case of error
+func (i *TypedIndex[T]) GetItems(ctx context.Context, key KeyInterface) ([]T, error) {
+	var items []T
+	if _, err := i.index.GetItemsWithContext(ctx, key, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Query gets the items of query from the index
+// returns the items, returns nil and an error in case of error
+func (i *TypedIndex[T]) Query(ctx context.Context, query QueryInterface) ([]T, error) {
+	var items []T
+	if err := i.index.QueryWithContext(ctx, query, &items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// QueryPage gets a single page of the items of query from the index, like QueryPageWithContext
+// returns the items and the token of the next page, empty if there are no more pages, returns nil, an empty token and an error in case of error
+func (i *TypedIndex[T]) QueryPage(ctx context.Context, query QueryInterface) ([]T, string, error) {
+	var items []T
+	nextPageToken, err := i.index.QueryPageWithContext(ctx, query, &items)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return items, nextPageToken, nil
+}
+
+// QueryAll returns the items of query from the index as a sequence to range over, like the package level QueryAll
+func (i *TypedIndex[T]) QueryAll(ctx context.Context, query QueryInterface) iter.Seq2[T, error] {
+	return QueryAll[T](ctx, i.index, query)
+}
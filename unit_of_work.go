@@ -0,0 +1,171 @@
+package djoemo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/guregu/dynamo"
+)
+
+const (
+	versionCondition = "attribute_not_exists(Version) OR Version = ?"
+	// notExistsCondition guards new items; it checks the hash key, so items saved without version are not overwritten
+	notExistsCondition = "attribute_not_exists($)"
+	unitOfWorkLoaded   = "loaded"
+	unitOfWorkNew      = "new"
+	unitOfWorkDeleted  = "deleted"
+)
+
+type unitOfWorkEntry struct {
+	state    string
+	key      KeyInterface
+	item     ModelInterface
+	snapshot map[string]*dynamodb.AttributeValue
+}
+
+// UnitOfWork tracks loaded, new and deleted models and commits their changes in one transaction
+type UnitOfWork struct {
+	repository *Repository
+	entries    []*unitOfWorkEntry
+	err        error
+}
+
+// RegisterLoaded registers an item loaded from the table; it is saved on commit if it was changed after registration
+func (uow *UnitOfWork) RegisterLoaded(key KeyInterface, item ModelInterface) {
+	snapshot, err := dynamo.MarshalItem(item)
+	if err != nil {
+		uow.setError(err)
+		return
+	}
+	uow.register(unitOfWorkLoaded, key, item, snapshot)
+}
+
+// RegisterNew registers an item that does not exist in the table yet; it is created on commit
+func (uow *UnitOfWork) RegisterNew(key KeyInterface, item ModelInterface) {
+	uow.register(unitOfWorkNew, key, item, nil)
+}
+
+// RegisterDeleted registers an item loaded from the table that is deleted on commit
+func (uow *UnitOfWork) RegisterDeleted(key KeyInterface, item ModelInterface) {
+	uow.register(unitOfWorkDeleted, key, item, nil)
+}
+
+// CommitWithContext saves all changed, new and deleted items in one transaction guarded by the version of each item.
+// Saved items get their version increased and their timestamps initialised, same as with OptimisticLockSaveWithContext;
+// if the commit fails, they are restored. The unit of work is empty after a successful commit and after a version did not match,
+// since its items are stale then and must be reloaded and registered again; after other errors the commit can be retried.
+// If a registration failed, e.g. because its key is invalid, nothing is committed and the unit of work is emptied, so it can be reused
+// returns true if all items were committed, returns false and nil if the version of an item on the server does not match,
+// returns false and an error in case of error
+func (uow *UnitOfWork) CommitWithContext(ctx context.Context) (committed bool, err error) {
+	if uow.err != nil {
+		err = uow.err
+		uow.entries = nil
+		uow.err = nil
+		return false, err
+	}
+
+	var restores []func()
+	defer func() {
+		if committed {
+			return
+		}
+		for _, restore := range restores {
+			restore()
+		}
+	}()
+
+	tx := TransactWrite()
+	for _, entry := range uow.entries {
+		switch entry.state {
+		case unitOfWorkLoaded:
+			current, err := dynamo.MarshalItem(entry.item)
+			if err != nil {
+				return false, err
+			}
+			if reflect.DeepEqual(current, entry.snapshot) {
+				continue
+			}
+			currentVersion := entry.item.GetVersion()
+			restores = append(restores, touchModel(entry.item))
+			tx.ConditionalPut(entry.key, entry.item, versionCondition, currentVersion)
+		case unitOfWorkNew:
+			restores = append(restores, touchModel(entry.item))
+			tx.ConditionalPut(entry.key, entry.item, notExistsCondition, *entry.key.HashKeyName())
+		case unitOfWorkDeleted:
+			tx.ConditionalDelete(entry.key, versionCondition, entry.item.GetVersion())
+		}
+	}
+
+	if len(tx.Items()) > 0 {
+		err = uow.repository.TransactWriteItemsWithContext(ctx, tx)
+		if err != nil {
+			var txErr *TransactionCanceledError
+			if errors.As(err, &txErr) && txErr.ConditionFailed() {
+				uow.entries = nil
+				return false, nil
+			}
+
+			return false, err
+		}
+	}
+
+	uow.entries = nil
+	return true, nil
+}
+
+// register adds the item to the unit of work; an item that is already registered under the same key changes its state.
+// An invalid key fails the commit
+func (uow *UnitOfWork) register(state string, key KeyInterface, item ModelInterface, snapshot map[string]*dynamodb.AttributeValue) {
+	if err := isValidKey(key); err != nil {
+		uow.setError(err)
+		return
+	}
+	for _, entry := range uow.entries {
+		if isSameKey(entry.key, key) {
+			entry.state = state
+			entry.item = item
+			if snapshot != nil {
+				entry.snapshot = snapshot
+			}
+			return
+		}
+	}
+
+	uow.entries = append(uow.entries, &unitOfWorkEntry{
+		state:    state,
+		key:      key,
+		item:     item,
+		snapshot: snapshot,
+	})
+}
+
+func (uow *UnitOfWork) setError(err error) {
+	if uow.err == nil {
+		uow.err = err
+	}
+}
+
+// touchModel increases the version and initialises the timestamps of a model before it is saved
+// returns a function that restores the model as it was before
+func touchModel(model ModelInterface) func() {
+	restore := func() {}
+	if value := reflect.ValueOf(model); value.Kind() == reflect.Pointer && !value.IsNil() {
+		saved := reflect.New(value.Elem().Type()).Elem()
+		saved.Set(value.Elem())
+		restore = func() { value.Elem().Set(saved) }
+	}
+
+	model.IncreaseVersion()
+	model.InitCreatedAt()
+	model.InitUpdatedAt()
+	return restore
+}
+
+func isSameKey(a, b KeyInterface) bool {
+	return a.TableName() == b.TableName() &&
+		reflect.DeepEqual(a.HashKey(), b.HashKey()) &&
+		reflect.DeepEqual(a.RangeKey(), b.RangeKey())
+}
@@ -0,0 +1,26 @@
+package djoemo
+
+import (
+	"context"
+)
+
+// UnitOfWorkInterface provides an interface to track changes of models and commit them together
+//
+//go:generate mockgen -source=unit_of_work_interface.go -destination=./mock/unit_of_work_interface.go -package=mock .
+type UnitOfWorkInterface interface {
+	// RegisterLoaded registers an item loaded from the table; it is saved on commit if it was changed after registration
+	RegisterLoaded(key KeyInterface, item ModelInterface)
+
+	// RegisterNew registers an item that does not exist in the table yet; it is created on commit
+	RegisterNew(key KeyInterface, item ModelInterface)
+
+	// RegisterDeleted registers an item loaded from the table that is deleted on commit
+	RegisterDeleted(key KeyInterface, item ModelInterface)
+
+	// CommitWithContext saves all changed, new and deleted items in one transaction guarded by the version of each item;
+	// the items are restored if the commit fails, after a version did not match they must be reloaded and registered again.
+	// If a registration failed, e.g. because its key is invalid, its error is returned and the unit of work is emptied
+	// returns true if all items were committed, returns false and nil if the version of an item on the server does not match,
+	// returns false and an error in case of error
+	CommitWithContext(ctx context.Context) (bool, error)
+}